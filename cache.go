@@ -0,0 +1,70 @@
+package sqlite3
+
+// PrepareCached is like [Conn.Prepare],
+// but it reuses statements previously prepared with the same SQL,
+// and closed with [Stmt.Close].
+//
+// Closing a cached statement resets it, clears its bindings,
+// and returns it to a least recently used cache,
+// from which statements are finalized when the cache is full.
+// A cached statement must not be used after it is closed,
+// and closing it again does nothing.
+//
+// SQLite automatically recompiles cached statements
+// after schema changes that affect them.
+// Statements followed by a tail (multiple statements) are not cached.
+func (c *Conn) PrepareCached(sql string) (stmt *Stmt, tail string, err error) {
+	if s := c.cache.get(sql); s != nil {
+		return s, "", nil
+	}
+	stmt, tail, err = c.PrepareFlags(sql, PREPARE_PERSISTENT)
+	if stmt != nil && tail == "" {
+		stmt.cached = true
+		stmt.sql = sql
+	}
+	return stmt, tail, err
+}
+
+// SetStmtCacheSize sets the maximum number of statements
+// kept by [Conn.PrepareCached] while closed.
+// The default is 16. A size of 0 disables caching.
+func (c *Conn) SetStmtCacheSize(size int) {
+	c.cache.size = max(size, 0)
+	c.cache.trim(c.cache.size)
+}
+
+const _STMT_CACHE_SIZE = 16
+
+type stmtCache struct {
+	idle []*Stmt // least recently used first
+	size int
+}
+
+func (cache *stmtCache) get(sql string) *Stmt {
+	for i := len(cache.idle) - 1; i >= 0; i-- {
+		if s := cache.idle[i]; s.sql == sql {
+			l := len(cache.idle) - 1
+			copy(cache.idle[i:], cache.idle[i+1:])
+			cache.idle[l] = nil
+			cache.idle = cache.idle[:l]
+			return s
+		}
+	}
+	return nil
+}
+
+func (cache *stmtCache) put(s *Stmt) {
+	cache.idle = append(cache.idle, s)
+	cache.trim(cache.size)
+}
+
+func (cache *stmtCache) trim(size int) {
+	if n := len(cache.idle) - size; n > 0 {
+		for i, s := range cache.idle[:n] {
+			s.cached = false
+			s.Close()
+			cache.idle[i] = nil
+		}
+		cache.idle = append(cache.idle[:0], cache.idle[n:]...)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/ncruces/go-sqlite3/internal/util"
 	"github.com/tetratelabs/wazero/api"
@@ -20,8 +21,19 @@ type Conn struct {
 
 	interrupt context.Context
 	pending   *Stmt
+	stmts     map[uint32]*Stmt
+	cache     stmtCache
 	arena     arena
 
+	commit     func() bool
+	rollback   func()
+	update     func(AuthorizerActionCode, string, string, int64)
+	preupdate  func(PreUpdate)
+	authorizer func(AuthorizerActionCode, string, string, string, string) AuthorizerReturnCode
+	trace      func(TraceEvent, any, any) error
+	busy       func(int) bool
+	wal        func(*Conn, string, int) error
+
 	handle uint32
 }
 
@@ -60,6 +72,7 @@ func newConn(filename string, flags OpenFlag) (conn *Conn, err error) {
 
 	c := &Conn{sqlite: sqlite}
 	c.arena = c.newArena(1024)
+	c.cache.size = _STMT_CACHE_SIZE
 	c.ctx = context.WithValue(c.ctx, connKey{}, c)
 	c.handle, err = c.openDB(filename, flags)
 	if err != nil {
@@ -130,6 +143,7 @@ func (c *Conn) Close() error {
 
 	c.pending.Close()
 	c.pending = nil
+	c.cache.trim(0)
 
 	r := c.call("sqlite3_close", uint64(c.handle))
 	if err := c.error(r); err != nil {
@@ -190,6 +204,10 @@ func (c *Conn) PrepareFlags(sql string, flags PrepareFlag) (stmt *Stmt, tail str
 	if stmt.handle == 0 {
 		return nil, "", nil
 	}
+	if c.stmts == nil {
+		c.stmts = map[uint32]*Stmt{}
+	}
+	c.stmts[stmt.handle] = stmt
 	return stmt, tail, nil
 }
 
@@ -220,6 +238,112 @@ func (c *Conn) Changes() int64 {
 	return int64(r)
 }
 
+// TableColumnMetadata returns metadata about a column of a table.
+// If schema is empty, all attached databases are searched for the table.
+// If column is empty, TableColumnMetadata only checks that the table exists.
+//
+// https://sqlite.org/c3ref/table_column_metadata.html
+func (c *Conn) TableColumnMetadata(schema, table, column string) (declType, collSeq string, notNull, primaryKey, autoInc bool, err error) {
+	defer c.arena.mark()()
+
+	var schemaPtr, columnPtr uint32
+	declTypePtr := c.arena.new(ptrlen)
+	collSeqPtr := c.arena.new(ptrlen)
+	notNullPtr := c.arena.new(ptrlen)
+	primaryKeyPtr := c.arena.new(ptrlen)
+	autoIncPtr := c.arena.new(ptrlen)
+	if schema != "" {
+		schemaPtr = c.arena.string(schema)
+	}
+	tablePtr := c.arena.string(table)
+	if column != "" {
+		columnPtr = c.arena.string(column)
+	}
+
+	r := c.call("sqlite3_table_column_metadata", uint64(c.handle),
+		uint64(schemaPtr), uint64(tablePtr), uint64(columnPtr),
+		uint64(declTypePtr), uint64(collSeqPtr),
+		uint64(notNullPtr), uint64(primaryKeyPtr), uint64(autoIncPtr))
+	if err = c.error(r); err == nil && column != "" {
+		if ptr := util.ReadUint32(c.mod, declTypePtr); ptr != 0 {
+			declType = util.ReadString(c.mod, ptr, _MAX_NAME)
+		}
+		if ptr := util.ReadUint32(c.mod, collSeqPtr); ptr != 0 {
+			collSeq = util.ReadString(c.mod, ptr, _MAX_NAME)
+		}
+		notNull = util.ReadUint32(c.mod, notNullPtr) != 0
+		autoInc = util.ReadUint32(c.mod, autoIncPtr) != 0
+		primaryKey = util.ReadUint32(c.mod, primaryKeyPtr) != 0
+	}
+	return
+}
+
+// TotalChanges returns the number of rows modified, inserted or deleted
+// by all INSERT, UPDATE or DELETE statements completed
+// since the database connection was opened.
+//
+// https://sqlite.org/c3ref/total_changes.html
+func (c *Conn) TotalChanges() int64 {
+	r := c.call("sqlite3_total_changes64", uint64(c.handle))
+	return int64(r)
+}
+
+// SetLastInsertRowID allows the application to set the value returned by
+// [Conn.LastInsertRowID].
+//
+// https://sqlite.org/c3ref/set_last_insert_rowid.html
+func (c *Conn) SetLastInsertRowID(id int64) {
+	c.call("sqlite3_set_last_insert_rowid", uint64(c.handle), uint64(id))
+}
+
+// ReadOnly determines if a database is read-only.
+// If schema is empty, the main database is checked.
+// The second result is false if there is no such attached database.
+//
+// https://sqlite.org/c3ref/db_readonly.html
+func (c *Conn) ReadOnly(schema string) (ro bool, ok bool) {
+	defer c.arena.mark()()
+	var schemaPtr uint32
+	if schema != "" {
+		schemaPtr = c.arena.string(schema)
+	}
+	r := int32(c.call("sqlite3_db_readonly", uint64(c.handle), uint64(schemaPtr)))
+	return r > 0, r >= 0
+}
+
+// TxnState returns the transaction state of a database.
+// If schema is empty, the highest transaction state of any schema is returned.
+// The second result is false if there is no such attached database.
+//
+// https://sqlite.org/c3ref/txn_state.html
+func (c *Conn) TxnState(schema string) (TxnState, bool) {
+	defer c.arena.mark()()
+	var schemaPtr uint32
+	if schema != "" {
+		schemaPtr = c.arena.string(schema)
+	}
+	r := int32(c.call("sqlite3_txn_state", uint64(c.handle), uint64(schemaPtr)))
+	return TxnState(max(r, 0)), r >= 0
+}
+
+// CacheFlush flushes all dirty pages of the database connection's
+// page cache to disk, for all attached databases.
+//
+// https://sqlite.org/c3ref/db_cacheflush.html
+func (c *Conn) CacheFlush() error {
+	r := c.call("sqlite3_db_cacheflush", uint64(c.handle))
+	return c.error(r)
+}
+
+// ReleaseMemory frees as much heap memory as possible
+// from the database connection.
+//
+// https://sqlite.org/c3ref/db_release_memory.html
+func (c *Conn) ReleaseMemory() error {
+	r := c.call("sqlite3_db_release_memory", uint64(c.handle))
+	return c.error(r)
+}
+
 // SetInterrupt interrupts a long-running query when a context is done.
 //
 // Subsequent uses of the connection will return [INTERRUPT]
@@ -273,6 +397,286 @@ func (c *Conn) checkInterrupt() {
 	}
 }
 
+// BusyTimeout sets a busy handler that sleeps for increasing amounts of time
+// while tables are locked, until at least timeout has elapsed,
+// or the context set by [Conn.SetInterrupt] is done.
+// A non-positive timeout clears any busy handler.
+//
+// BusyTimeout replaces any busy handler set with [Conn.BusyHandler],
+// and overrides PRAGMA busy_timeout.
+//
+// https://sqlite.org/c3ref/busy_timeout.html
+func (c *Conn) BusyTimeout(timeout time.Duration) error {
+	if timeout <= 0 {
+		return c.BusyHandler(nil)
+	}
+
+	var start time.Time
+	return c.BusyHandler(func(count int) (retry bool) {
+		// Same schedule as sqliteDefaultBusyCallback, in milliseconds.
+		const delays = "\x01\x02\x05\x0a\x0f\x14\x19\x19\x19\x32\x32\x64"
+
+		now := time.Now()
+		if count == 0 {
+			start = now
+		}
+
+		delay := time.Duration(delays[min(count, len(delays)-1)]) * time.Millisecond
+		if left := timeout - now.Sub(start); delay > left {
+			delay = left
+		}
+		if delay <= 0 {
+			return false
+		}
+
+		var done <-chan struct{}
+		if c.interrupt != nil {
+			done = c.interrupt.Done()
+		}
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-done:
+			return false
+		case <-timer.C:
+			return true
+		}
+	})
+}
+
+// BusyHandler registers a callback to handle [BUSY] errors.
+// The callback is invoked with the number of times it has been invoked
+// for the same locking event, and should return true to retry,
+// or false to fail the operation with [BUSY].
+// The callback is not invoked once the context set by [Conn.SetInterrupt] is done.
+// A nil callback clears any busy handler.
+//
+// BusyHandler overrides PRAGMA busy_timeout.
+//
+// https://sqlite.org/c3ref/busy_handler.html
+func (c *Conn) BusyHandler(cb func(count int) (retry bool)) error {
+	var enable uint64
+	if cb != nil {
+		enable = 1
+	}
+	r := c.call("sqlite3_busy_handler_go", uint64(c.handle), enable)
+	if err := c.error(r); err != nil {
+		return err
+	}
+	c.busy = cb
+	return nil
+}
+
+func busyCallback(ctx context.Context, mod api.Module, pDB uint32, count int32) (retry uint32) {
+	if c, ok := ctx.Value(connKey{}).(*Conn); ok && c.handle == pDB && c.busy != nil {
+		if interrupt := c.interrupt; interrupt == nil || interrupt.Err() == nil {
+			if c.busy(int(count)) {
+				retry = 1
+			}
+		}
+	}
+	return retry
+}
+
+// WALCheckpoint runs a checkpoint on database schema of the connection,
+// or on all attached databases if schema is empty.
+// It returns the total number of frames in the WAL file,
+// and the number of frames checkpointed.
+//
+// https://sqlite.org/c3ref/wal_checkpoint_v2.html
+func (c *Conn) WALCheckpoint(schema string, mode CheckpointMode) (nLog, nCkpt int, err error) {
+	defer c.arena.mark()()
+	nLogPtr := c.arena.new(ptrlen)
+	nCkptPtr := c.arena.new(ptrlen)
+	schemaPtr := c.arena.string(schema)
+	r := c.call("sqlite3_wal_checkpoint_v2",
+		uint64(c.handle), uint64(schemaPtr), uint64(mode),
+		uint64(nLogPtr), uint64(nCkptPtr))
+	nLog = int(int32(util.ReadUint32(c.mod, nLogPtr)))
+	nCkpt = int(int32(util.ReadUint32(c.mod, nCkptPtr)))
+	return nLog, nCkpt, c.error(r)
+}
+
+// WALAutoCheckpoint configures WAL auto-checkpoints:
+// a checkpoint is run whenever the WAL reaches pages in size.
+// A non-positive value disables auto-checkpoints.
+//
+// WALAutoCheckpoint replaces any hook set with [Conn.WALHook].
+//
+// https://sqlite.org/c3ref/wal_autocheckpoint.html
+func (c *Conn) WALAutoCheckpoint(pages int) error {
+	r := c.call("sqlite3_wal_autocheckpoint", uint64(c.handle), uint64(pages))
+	c.wal = nil
+	return c.error(r)
+}
+
+// Limit changes the run-time limit for category to value,
+// and returns the prior value of the limit.
+// If value is negative, the limit is not changed.
+// Limits cannot be raised above their compile-time maximums.
+//
+// https://sqlite.org/c3ref/limit.html
+func (c *Conn) Limit(id LimitCategory, value int) (old int) {
+	r := c.call("sqlite3_limit", uint64(c.handle), uint64(id), uint64(value))
+	return int(int32(r))
+}
+
+// Status returns the current and highwater values of a connection status counter.
+// If reset is true, the highwater value is reset to the current value.
+//
+// https://sqlite.org/c3ref/db_status.html
+func (c *Conn) Status(op DBStatus, reset bool) (current, highwater int, err error) {
+	defer c.arena.mark()()
+	curPtr := c.arena.new(ptrlen)
+	hiPtr := c.arena.new(ptrlen)
+
+	var i uint64
+	if reset {
+		i = 1
+	}
+
+	r := c.call("sqlite3_db_status", uint64(c.handle),
+		uint64(op), uint64(curPtr), uint64(hiPtr), i)
+	if err = c.error(r); err == nil {
+		current = int(int32(util.ReadUint32(c.mod, curPtr)))
+		highwater = int(int32(util.ReadUint32(c.mod, hiPtr)))
+	}
+	return
+}
+
+// GlobalStatus returns the current and highwater values of a library status counter.
+// If reset is true, the highwater value is reset to the current value.
+//
+// Each connection runs in its own WASM instance, with its own heap,
+// so these counters only measure the library instance of the connection:
+// e.g. [STATUS_MEMORY_USED] reports the WASM heap memory used by SQLite.
+//
+// https://sqlite.org/c3ref/status.html
+func (c *Conn) GlobalStatus(op Status, reset bool) (current, highwater int, err error) {
+	defer c.arena.mark()()
+	curPtr := c.arena.new(8)
+	hiPtr := c.arena.new(8)
+
+	var i uint64
+	if reset {
+		i = 1
+	}
+
+	r := c.call("sqlite3_status64",
+		uint64(op), uint64(curPtr), uint64(hiPtr), i)
+	if err = c.sqlite.error(r, 0); err == nil {
+		current = int(util.ReadUint64(c.mod, curPtr))
+		highwater = int(util.ReadUint64(c.mod, hiPtr))
+	}
+	return
+}
+
+// Config sets or queries a boolean database configuration option.
+// If value is given, the option is set to value.
+// Config returns the current (new) value of the option.
+//
+// https://sqlite.org/c3ref/db_config.html
+func (c *Conn) Config(op DBConfig, value ...bool) (bool, error) {
+	defer c.arena.mark()()
+	resPtr := c.arena.new(ptrlen)
+
+	arg := -1
+	if len(value) > 0 {
+		arg = 0
+		if value[0] {
+			arg = 1
+		}
+	}
+
+	r := c.call("sqlite3_db_config_go", uint64(c.handle),
+		uint64(op), uint64(arg), uint64(resPtr))
+	if err := c.error(r); err != nil {
+		return false, err
+	}
+	return util.ReadUint32(c.mod, resPtr) != 0, nil
+}
+
+// ResetDatabase deletes all content and schema from the main database,
+// resetting it to an empty database.
+//
+// https://sqlite.org/c3ref/c_dbconfig_defensive.html#sqlitedbconfigresetdatabase
+func (c *Conn) ResetDatabase() error {
+	if _, err := c.Config(DBCONFIG_RESET_DATABASE, true); err != nil {
+		return err
+	}
+	err := c.Exec(`VACUUM`)
+	if _, cerr := c.Config(DBCONFIG_RESET_DATABASE, false); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Serialize returns a copy of the database schema,
+// with the same contents the database file would have on disk.
+// If schema is empty, the main database is serialized.
+//
+// https://sqlite.org/c3ref/serialize.html
+func (c *Conn) Serialize(schema string) ([]byte, error) {
+	defer c.arena.mark()()
+	var schemaPtr uint32
+	if schema != "" {
+		schemaPtr = c.arena.string(schema)
+	}
+	sizePtr := c.arena.new(8)
+
+	ptr := uint32(c.call("sqlite3_serialize", uint64(c.handle),
+		uint64(schemaPtr), uint64(sizePtr), 0))
+	size := int64(util.ReadUint64(c.mod, sizePtr))
+	if size < 0 {
+		return nil, c.sqlite.error(uint64(ERROR), 0)
+	}
+
+	if ptr == 0 && size > 0 {
+		return nil, NOMEM
+	}
+
+	data := make([]byte, size)
+	if ptr != 0 {
+		copy(data, util.View(c.mod, ptr, uint64(size)))
+		c.call("sqlite3_free", uint64(ptr))
+	}
+	return data, nil
+}
+
+// Deserialize replaces the database schema with a copy of data,
+// which must have the format of a database file.
+// If schema is empty, the main database is replaced.
+//
+// The deserialized database is held in memory, and is private to the connection:
+// it is not shared with other connections, even if the database
+// was previously opened with the [memdb] VFS.
+// If readOnly is false, the in-memory database can be written to,
+// and grows as needed.
+//
+// https://sqlite.org/c3ref/deserialize.html
+//
+// [memdb]: https://pkg.go.dev/github.com/ncruces/go-sqlite3/vfs/memdb
+func (c *Conn) Deserialize(schema string, data []byte, readOnly bool) error {
+	defer c.arena.mark()()
+	var schemaPtr uint32
+	if schema != "" {
+		schemaPtr = c.arena.string(schema)
+	}
+
+	flags := uint64(_DESERIALIZE_FREEONCLOSE)
+	if readOnly {
+		flags |= _DESERIALIZE_READONLY
+	} else {
+		flags |= _DESERIALIZE_RESIZEABLE
+	}
+
+	// SQLite takes ownership of the copy, even on failure.
+	ptr := c.newBytes(data)
+	r := c.call("sqlite3_deserialize", uint64(c.handle), uint64(schemaPtr),
+		uint64(ptr), uint64(len(data)), uint64(len(data)), flags)
+	return c.error(r)
+}
+
 // Pragma executes a PRAGMA statement and returns any results.
 //
 // https://sqlite.org/pragma.html
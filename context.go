@@ -218,3 +218,14 @@ func (ctx Context) ResultError(err error) {
 			uint64(ctx.handle), uint64(code))
 	}
 }
+
+// VTabNoChange may return true if a column is being fetched
+// as part of an UPDATE operation during which the column value will not change.
+// [VTabCursor.Column] can then return early,
+// and the virtual table can use [Value.NoChange] in [VTabUpdater.Update].
+//
+// https://sqlite.org/c3ref/vtab_nochange.html
+func (ctx Context) VTabNoChange() bool {
+	r := ctx.c.call("sqlite3_vtab_nochange", uint64(ctx.handle))
+	return r != 0
+}
@@ -24,11 +24,20 @@
 // "sqlite" encodes as SQLite and decodes any [format] supported by SQLite;
 // "rfc3339" encodes and decodes RFC 3339 only.
 //
+// Run-time [limits] can be specified using "_limit_" followed by the limit name:
+//
+//	sql.Open("sqlite3", "file:demo.db?_limit_sql_length=10000&_limit_attached=0")
+//
+// Possible names are: "length", "sql_length", "column", "expr_depth",
+// "compound_select", "vdbe_op", "function_arg", "attached",
+// "like_pattern_length", "variable_number", "trigger_depth", "worker_threads".
+//
 // [PRAGMA] statements can be specified using "_pragma":
 //
 //	sql.Open("sqlite3", "file:demo.db?_pragma=busy_timeout(10000)")
 //
-// If no PRAGMAs are specified, a busy timeout of 1 minute is set.
+// If no PRAGMAs are specified, a busy timeout of 1 minute is set,
+// which stops early if the context of the blocked operation is done.
 //
 // Order matters:
 // busy timeout and locking mode should be the first PRAGMAs set, in that order.
@@ -38,6 +47,7 @@
 // [format]: https://sqlite.org/lang_datefunc.html#time_values
 // [TRANSACTION]: https://sqlite.org/lang_transaction.html#deferred_immediate_and_exclusive_transactions
 // [read-only]: https://pkg.go.dev/database/sql#TxOptions
+// [limits]: https://sqlite.org/limits.html
 package driver
 
 import (
@@ -48,6 +58,7 @@ import (
 	"fmt"
 	"io"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -106,6 +117,10 @@ func newConnector(name string, init func(*sqlite3.Conn) error) (*connector, erro
 			txlock = query.Get("_txlock")
 			timefmt = query.Get("_timefmt")
 			c.pragmas = query.Has("_pragma")
+			c.limits, err = parseLimits(query)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -135,8 +150,47 @@ func newConnector(name string, init func(*sqlite3.Conn) error) (*connector, erro
 	return &c, nil
 }
 
+var limitNames = map[string]sqlite3.LimitCategory{
+	"length":              sqlite3.LIMIT_LENGTH,
+	"sql_length":          sqlite3.LIMIT_SQL_LENGTH,
+	"column":              sqlite3.LIMIT_COLUMN,
+	"expr_depth":          sqlite3.LIMIT_EXPR_DEPTH,
+	"compound_select":     sqlite3.LIMIT_COMPOUND_SELECT,
+	"vdbe_op":             sqlite3.LIMIT_VDBE_OP,
+	"function_arg":        sqlite3.LIMIT_FUNCTION_ARG,
+	"attached":            sqlite3.LIMIT_ATTACHED,
+	"like_pattern_length": sqlite3.LIMIT_LIKE_PATTERN_LENGTH,
+	"variable_number":     sqlite3.LIMIT_VARIABLE_NUMBER,
+	"trigger_depth":       sqlite3.LIMIT_TRIGGER_DEPTH,
+	"worker_threads":      sqlite3.LIMIT_WORKER_THREADS,
+}
+
+func parseLimits(query url.Values) (map[sqlite3.LimitCategory]int, error) {
+	var limits map[sqlite3.LimitCategory]int
+	for key, values := range query {
+		name, ok := strings.CutPrefix(key, "_limit_")
+		if !ok {
+			continue
+		}
+		id, ok := limitNames[name]
+		if !ok {
+			return nil, fmt.Errorf("sqlite3: invalid limit: %s", key)
+		}
+		value, err := strconv.Atoi(values[len(values)-1])
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("sqlite3: invalid %s: %s", key, values[len(values)-1])
+		}
+		if limits == nil {
+			limits = map[sqlite3.LimitCategory]int{}
+		}
+		limits[id] = value
+	}
+	return limits, nil
+}
+
 type connector struct {
 	init    func(*sqlite3.Conn) error
+	limits  map[sqlite3.LimitCategory]int
 	name    string
 	txBegin string
 	tmRead  sqlite3.TimeFormat
@@ -168,8 +222,12 @@ func (n *connector) Connect(ctx context.Context) (_ driver.Conn, err error) {
 	old := c.Conn.SetInterrupt(ctx)
 	defer c.Conn.SetInterrupt(old)
 
+	for id, value := range n.limits {
+		c.Conn.Limit(id, value)
+	}
+
 	if !n.pragmas {
-		err = c.Conn.Exec(`PRAGMA busy_timeout=60000`)
+		err = c.Conn.BusyTimeout(60 * time.Second)
 		if err != nil {
 			return nil, err
 		}
@@ -284,7 +342,7 @@ func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, e
 	old := c.Conn.SetInterrupt(ctx)
 	defer c.Conn.SetInterrupt(old)
 
-	s, tail, err := c.Conn.Prepare(query)
+	s, tail, err := c.Conn.PrepareCached(query)
 	if err != nil {
 		return nil, err
 	}
@@ -310,7 +368,18 @@ func (c *conn) ExecContext(ctx context.Context, query string, args []driver.Name
 	old := c.Conn.SetInterrupt(ctx)
 	defer c.Conn.SetInterrupt(old)
 
-	err := c.Conn.Exec(query)
+	s, tail, err := c.Conn.PrepareCached(query)
+	if err != nil {
+		return nil, err
+	}
+	if s != nil {
+		err = s.Exec()
+		s.Close()
+	}
+	if err == nil && tail != "" {
+		// Multiple statements.
+		err = c.Conn.Exec(tail)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -522,6 +591,20 @@ func (r *rows) ColumnTypeDatabaseTypeName(index int) string {
 	return strings.TrimSpace(decltype)
 }
 
+func (r *rows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	table := r.Stmt.ColumnTableName(index)
+	if table == "" {
+		return false, false
+	}
+	_, _, notNull, _, _, err := r.Stmt.Conn().TableColumnMetadata(
+		r.Stmt.ColumnDatabaseName(index), table,
+		r.Stmt.ColumnOriginName(index))
+	if err != nil {
+		return false, false
+	}
+	return !notNull, true
+}
+
 func (r *rows) Next(dest []driver.Value) error {
 	old := r.Stmt.Conn().SetInterrupt(r.ctx)
 	defer r.Stmt.Conn().SetInterrupt(old)
@@ -8,6 +8,7 @@ import (
 	"math"
 	"net/url"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -124,6 +125,46 @@ func Test_Open_txLock_invalid(t *testing.T) {
 	}
 }
 
+func Test_Open_limit(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite3", "file::memory:?_limit_attached=0&_limit_sql_length=100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`ATTACH ':memory:' AS other`)
+	if err == nil {
+		t.Error("want error")
+	}
+
+	_, err = db.Exec(`SELECT '` + strings.Repeat("x", 100) + `'`)
+	if !errors.Is(err, sqlite3.TOOBIG) {
+		t.Errorf("got %v, want sqlite3.TOOBIG", err)
+	}
+}
+
+func Test_Open_limit_invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := sql.Open("sqlite3", "file::memory:?_limit_columns=10")
+	if err == nil {
+		t.Fatal("want error")
+	}
+	if got := err.Error(); got != `sqlite3: invalid limit: _limit_columns` {
+		t.Error("got message:", got)
+	}
+
+	_, err = sql.Open("sqlite3", "file::memory:?_limit_column=many")
+	if err == nil {
+		t.Fatal("want error")
+	}
+	if got := err.Error(); got != `sqlite3: invalid _limit_column: many` {
+		t.Error("got message:", got)
+	}
+}
+
 func Test_BeginTx(t *testing.T) {
 	t.Parallel()
 
@@ -332,3 +373,37 @@ func Test_time(t *testing.T) {
 		})
 	}
 }
+
+func Test_ColumnTypeNullable(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE test (a TEXT NOT NULL, b TEXT)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query(`SELECT a, b, a || b FROM test`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct{ nullable, ok bool }{{false, true}, {true, true}, {false, false}}
+	for i, typ := range types {
+		nullable, ok := typ.Nullable()
+		if nullable != want[i].nullable || ok != want[i].ok {
+			t.Errorf("column %d: got (%v, %v), want %v", i, nullable, ok, want[i])
+		}
+	}
+}
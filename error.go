@@ -15,6 +15,7 @@ type Error struct {
 	str  string
 	msg  string
 	sql  string
+	stmt string
 	code uint64
 }
 
@@ -96,6 +97,14 @@ func (e *Error) SQL() string {
 	return e.sql
 }
 
+// ExpandedSQL returns the SQL text, with bound parameters expanded,
+// of the prepared statement that failed.
+// It returns an empty string if the error did not come from a [Stmt],
+// or if the SQLite binary does not export sqlite3_expanded_sql.
+func (e *Error) ExpandedSQL() string {
+	return e.stmt
+}
+
 // Error implements the error interface.
 func (e ErrorCode) Error() string {
 	return util.ErrorCodeString(uint32(e))
@@ -0,0 +1,177 @@
+package sqlite3
+
+import (
+	"strings"
+
+	"github.com/ncruces/go-sqlite3/internal/util"
+)
+
+// PlanNode is a node of a query plan,
+// as returned by [Stmt.QueryPlan].
+//
+// https://sqlite.org/eqp.html
+type PlanNode struct {
+	ID     int
+	Parent int
+	Detail string
+}
+
+// QueryPlan returns the query plan of the prepared statement,
+// as if the statement was prefixed by EXPLAIN QUERY PLAN.
+// Bound parameters are taken into account.
+// The statement is reset.
+//
+// https://sqlite.org/c3ref/stmt_explain.html
+func (s *Stmt) QueryPlan() ([]PlanNode, error) {
+	if err := s.Reset(); err != nil {
+		return nil, err
+	}
+
+	mode := s.c.call("sqlite3_stmt_isexplain", uint64(s.handle))
+	r := s.c.call("sqlite3_stmt_explain", uint64(s.handle), 2)
+	if err := s.c.error(r); err != nil {
+		return nil, err
+	}
+
+	var plan []PlanNode
+	for s.Step() {
+		plan = append(plan, PlanNode{
+			ID:     s.ColumnInt(0),
+			Parent: s.ColumnInt(1),
+			Detail: s.ColumnText(3),
+		})
+	}
+	err := s.Reset()
+
+	r = s.c.call("sqlite3_stmt_explain", uint64(s.handle), mode)
+	if err == nil {
+		err = s.c.error(r)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// FormatQueryPlan renders a query plan as text,
+// the same way the sqlite3 command-line shell does.
+func FormatQueryPlan(plan []PlanNode) string {
+	if len(plan) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	if detail := plan[0].Detail; strings.HasPrefix(detail, "-") {
+		if len(plan) == 1 {
+			return ""
+		}
+		b.WriteString(detail[min(3, len(detail)):])
+		plan = plan[1:]
+	} else {
+		b.WriteString("QUERY PLAN")
+	}
+	b.WriteByte('\n')
+
+	var render func(id int, prefix string)
+	render = func(id int, prefix string) {
+		var children []PlanNode
+		for _, n := range plan {
+			if n.Parent == id {
+				children = append(children, n)
+			}
+		}
+		for i, n := range children {
+			last := i == len(children)-1
+			b.WriteString(prefix)
+			if last {
+				b.WriteString("`--")
+			} else {
+				b.WriteString("|--")
+			}
+			b.WriteString(n.Detail)
+			b.WriteByte('\n')
+			if last {
+				render(n.ID, prefix+"   ")
+			} else {
+				render(n.ID, prefix+"|  ")
+			}
+		}
+	}
+	render(0, "")
+	return b.String()
+}
+
+// ScanStatus is the profiling information of a query plan element,
+// as returned by [Stmt.ScanStatus].
+//
+// https://sqlite.org/c3ref/c_scanstat_est.html
+type ScanStatus struct {
+	// ID and Parent match the ID and Parent of a [PlanNode].
+	ID      int
+	Parent  int
+	Name    string  // The name of the table or index, if any.
+	Explain string  // The EXPLAIN QUERY PLAN detail.
+	Loops   int64   // Number of times the loop was run, or -1.
+	Visits  int64   // Number of rows visited, or -1.
+	EstRows float64 // Estimated number of rows output per loop, or -1.
+	Cycles  int64   // Number of cycles spent, or -1.
+}
+
+// ScanStatus returns profiling information about the query plan elements
+// of the prepared statement, accumulated since it was prepared,
+// or since [Stmt.ResetScanStatus] was last called.
+//
+// https://sqlite.org/c3ref/stmt_scanstatus.html
+func (s *Stmt) ScanStatus() []ScanStatus {
+	defer s.c.arena.mark()()
+	outPtr := s.c.arena.new(8)
+
+	get := func(idx, op int) bool {
+		r := s.c.call("sqlite3_stmt_scanstatus_v2", uint64(s.handle),
+			uint64(idx), uint64(op), _SCANSTAT_COMPLEX, uint64(outPtr))
+		return r == 0
+	}
+	getInt := func(idx, op int) int64 {
+		if get(idx, op) {
+			return int64(util.ReadUint64(s.c.mod, outPtr))
+		}
+		return -1
+	}
+	getString := func(idx, op int) string {
+		if get(idx, op) {
+			if ptr := util.ReadUint32(s.c.mod, outPtr); ptr != 0 {
+				return util.ReadString(s.c.mod, ptr, _MAX_SQL_LENGTH)
+			}
+		}
+		return ""
+	}
+
+	var status []ScanStatus
+	for i := 0; get(i, _SCANSTAT_SELECTID); i++ {
+		id := int(int32(util.ReadUint32(s.c.mod, outPtr)))
+		st := ScanStatus{
+			ID:      id,
+			Name:    getString(i, _SCANSTAT_NAME),
+			Explain: getString(i, _SCANSTAT_EXPLAIN),
+			Loops:   getInt(i, _SCANSTAT_NLOOP),
+			Visits:  getInt(i, _SCANSTAT_NVISIT),
+			Cycles:  getInt(i, _SCANSTAT_NCYCLE),
+			EstRows: -1,
+		}
+		if get(i, _SCANSTAT_PARENTID) {
+			st.Parent = int(int32(util.ReadUint32(s.c.mod, outPtr)))
+		}
+		if get(i, _SCANSTAT_EST) {
+			st.EstRows = util.ReadFloat64(s.c.mod, outPtr)
+		}
+		status = append(status, st)
+	}
+	return status
+}
+
+// ResetScanStatus zeroes the profiling information returned by [Stmt.ScanStatus].
+//
+// https://sqlite.org/c3ref/stmt_scanstatus_reset.html
+func (s *Stmt) ResetScanStatus() {
+	s.c.call("sqlite3_stmt_scanstatus_reset", uint64(s.handle))
+}
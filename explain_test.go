@@ -0,0 +1,28 @@
+package sqlite3
+
+import "testing"
+
+func TestFormatQueryPlan(t *testing.T) {
+	t.Parallel()
+
+	if got := FormatQueryPlan(nil); got != "" {
+		t.Errorf("got %q, want empty", got)
+	}
+
+	got := FormatQueryPlan([]PlanNode{
+		{ID: 2, Parent: 0, Detail: "SCAN t1"},
+		{ID: 4, Parent: 0, Detail: "CORRELATED SCALAR SUBQUERY 1"},
+		{ID: 7, Parent: 4, Detail: "SEARCH t2 USING INDEX i2 (b=?)"},
+		{ID: 9, Parent: 4, Detail: "USE TEMP B-TREE FOR ORDER BY"},
+		{ID: 12, Parent: 0, Detail: "USE TEMP B-TREE FOR DISTINCT"},
+	})
+	want := "QUERY PLAN\n" +
+		"|--SCAN t1\n" +
+		"|--CORRELATED SCALAR SUBQUERY 1\n" +
+		"|  |--SEARCH t2 USING INDEX i2 (b=?)\n" +
+		"|  `--USE TEMP B-TREE FOR ORDER BY\n" +
+		"`--USE TEMP B-TREE FOR DISTINCT\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,219 @@
+package structs
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ncruces/go-sqlite3"
+)
+
+type column struct {
+	name    string
+	decl    string
+	field   int
+	key     bool
+	keyKind keyKind
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func parseColumns(typ reflect.Type) ([]column, error) {
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("structs: unsupported type: %v", typ)
+	}
+
+	var columns []column
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(f.Tag.Get("db"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		col := column{name: name, field: i}
+		col.decl, col.keyKind = declType(f.Type)
+		if col.decl == "" {
+			return nil, fmt.Errorf("structs: unsupported field type: %s %v", f.Name, f.Type)
+		}
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "":
+			case "key":
+				if col.keyKind == keyNone {
+					return nil, fmt.Errorf("structs: unsupported key type: %s %v", f.Name, f.Type)
+				}
+				col.key = true
+			default:
+				return nil, fmt.Errorf("structs: unsupported tag option: %s %q", f.Name, opt)
+			}
+		}
+		columns = append(columns, col)
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("structs: no columns: %v", typ)
+	}
+	return columns, nil
+}
+
+func declareTable(columns []column) string {
+	var str strings.Builder
+	str.WriteString("CREATE TABLE x(")
+	for i, col := range columns {
+		if i > 0 {
+			str.WriteString(",")
+		}
+		str.WriteString(sqlite3.QuoteIdentifier(col.name))
+		str.WriteString(" ")
+		str.WriteString(col.decl)
+	}
+	str.WriteString(")")
+	return str.String()
+}
+
+func declType(typ reflect.Type) (string, keyKind) {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ == timeType {
+		return "DATETIME", keyNone
+	}
+	switch typ.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "INTEGER", keyInt
+	case reflect.Float32, reflect.Float64:
+		return "REAL", keyFloat
+	case reflect.String:
+		return "TEXT", keyText
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return "BLOB", keyNone
+		}
+	}
+	return "", keyNone
+}
+
+// keyOf returns the key of a field, or false if it is NULL.
+func keyOf(v reflect.Value) (key, bool) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return key{}, false
+		}
+		v = v.Elem()
+	}
+	switch {
+	case v.CanInt():
+		return key{kind: keyInt, i: v.Int()}, true
+	case v.CanUint():
+		return key{kind: keyInt, i: int64(v.Uint())}, true
+	case v.CanFloat():
+		f := v.Float()
+		return key{kind: keyFloat, f: f}, !math.IsNaN(f)
+	case v.Kind() == reflect.Bool:
+		var i int64
+		if v.Bool() {
+			i = 1
+		}
+		return key{kind: keyInt, i: i}, true
+	case v.Kind() == reflect.String:
+		return key{kind: keyText, s: v.String()}, true
+	}
+	return key{}, false
+}
+
+func result(ctx *sqlite3.Context, v reflect.Value) error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			ctx.ResultNull()
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Type() == timeType:
+		ctx.ResultTime(v.Interface().(time.Time), sqlite3.TimeFormatDefault)
+
+	case v.CanInt():
+		ctx.ResultInt64(v.Int())
+
+	case v.CanUint():
+		i64 := int64(v.Uint())
+		if i64 < 0 {
+			return fmt.Errorf("structs: integer field overflow:%.0w %d", sqlite3.MISMATCH, v.Uint())
+		}
+		ctx.ResultInt64(i64)
+
+	case v.CanFloat():
+		ctx.ResultFloat(v.Float())
+
+	case v.Kind() == reflect.Bool:
+		ctx.ResultBool(v.Bool())
+
+	case v.Kind() == reflect.String:
+		ctx.ResultText(v.String())
+
+	default:
+		ctx.ResultBlob(v.Bytes())
+	}
+	return nil
+}
+
+func assign(v reflect.Value, arg sqlite3.Value) error {
+	if v.Kind() == reflect.Pointer {
+		if arg.Type() == sqlite3.NULL {
+			v.SetZero()
+			return nil
+		}
+		ptr := reflect.New(v.Type().Elem())
+		if err := assign(ptr.Elem(), arg); err != nil {
+			return err
+		}
+		v.Set(ptr)
+		return nil
+	}
+
+	switch {
+	case v.Type() == timeType:
+		v.Set(reflect.ValueOf(arg.Time(sqlite3.TimeFormatAuto)))
+
+	case v.CanInt():
+		i := arg.Int64()
+		if v.OverflowInt(i) {
+			return fmt.Errorf("structs: integer value overflow:%.0w %d", sqlite3.MISMATCH, i)
+		}
+		v.SetInt(i)
+
+	case v.CanUint():
+		i := arg.Int64()
+		if i < 0 || v.OverflowUint(uint64(i)) {
+			return fmt.Errorf("structs: integer value overflow:%.0w %d", sqlite3.MISMATCH, i)
+		}
+		v.SetUint(uint64(i))
+
+	case v.CanFloat():
+		v.SetFloat(arg.Float())
+
+	case v.Kind() == reflect.Bool:
+		v.SetBool(arg.Bool())
+
+	case v.Kind() == reflect.String:
+		v.SetString(arg.Text())
+
+	default:
+		v.SetBytes(arg.Blob(nil))
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package structs
+
+import (
+	"cmp"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/ncruces/go-sqlite3"
+)
+
+type keyKind uint8
+
+const (
+	keyNone keyKind = iota
+	keyInt
+	keyFloat
+	keyText
+)
+
+// A key is the value of a key field, or of a constraint.
+type key struct {
+	kind keyKind
+	i    int64
+	f    float64
+	s    string
+}
+
+// An index holds the rows of a table with non-NULL keys, sorted by key.
+type index struct {
+	rows []int
+	keys []key
+}
+
+// invalidate drops all indexes.
+func (t *table) invalidate() {
+	t.indexes = nil
+}
+
+// index returns the index for a key column, building it if needed.
+func (t *table) index(col int) *index {
+	// Changes to the slice that don't go through SQL are
+	// only detected if they reallocate or change its length.
+	if ptr, len := t.data.Pointer(), t.data.Len(); ptr != t.ptr || len != t.len {
+		t.ptr, t.len = ptr, len
+		t.invalidate()
+	}
+	if idx, ok := t.indexes[col]; ok {
+		return idx
+	}
+
+	idx := &index{}
+	field := t.columns[col].field
+	for i, n := 0, t.data.Len(); i < n; i++ {
+		if k, ok := keyOf(t.data.Index(i).Field(field)); ok {
+			idx.rows = append(idx.rows, i)
+			idx.keys = append(idx.keys, k)
+		}
+	}
+	sort.Stable(idx)
+
+	if t.indexes == nil {
+		t.indexes = map[int]*index{}
+	}
+	t.indexes[col] = idx
+	return idx
+}
+
+func (idx *index) Len() int           { return len(idx.rows) }
+func (idx *index) Less(i, j int) bool { return compare(idx.keys[i], idx.keys[j]) < 0 }
+func (idx *index) Swap(i, j int) {
+	idx.rows[i], idx.rows[j] = idx.rows[j], idx.rows[i]
+	idx.keys[i], idx.keys[j] = idx.keys[j], idx.keys[i]
+}
+
+// search returns the rows that may match a plan chosen by BestIndex,
+// or false if the arguments can't be used with the index,
+// and a full table scan is needed.
+// Bounds are always inclusive, as SQLite double checks every row.
+func (t *table) search(idxNum int, arg []sqlite3.Value) ([]int, bool) {
+	col, plan := idxNum>>3-1, idxNum&7
+
+	// The rowid.
+	if col < 0 {
+		if arg[0].Type() != sqlite3.INTEGER {
+			return nil, false
+		}
+		if i := arg[0].Int64(); 0 <= i && i < int64(t.data.Len()) {
+			return []int{int(i)}, true
+		}
+		return nil, true
+	}
+
+	var lower, upper key
+	kind := t.columns[col].keyKind
+	if plan&planEQ != 0 {
+		k, ok := keyArg(kind, arg[0])
+		if !ok {
+			return nil, false
+		}
+		lower, upper = k, k
+	} else {
+		if plan&planLower != 0 {
+			k, ok := keyArg(kind, arg[0])
+			if !ok {
+				return nil, false
+			}
+			lower = k
+			arg = arg[1:]
+		}
+		if plan&planUpper != 0 {
+			k, ok := keyArg(kind, arg[0])
+			if !ok {
+				return nil, false
+			}
+			upper = k
+		}
+	}
+
+	idx := t.index(col)
+	lo, hi := 0, len(idx.keys)
+	if lower.kind != keyNone {
+		lo = sort.Search(len(idx.keys), func(i int) bool {
+			return compare(idx.keys[i], lower) >= 0
+		})
+	}
+	if upper.kind != keyNone {
+		hi = sort.Search(len(idx.keys), func(i int) bool {
+			return compare(idx.keys[i], upper) > 0
+		})
+	}
+	if lo >= hi {
+		return nil, true
+	}
+	return idx.rows[lo:hi], true
+}
+
+// keyArg converts a constraint argument to a key
+// that can be compared with the keys of a column.
+func keyArg(kind keyKind, arg sqlite3.Value) (key, bool) {
+	switch kind {
+	case keyInt, keyFloat:
+		switch arg.Type() {
+		case sqlite3.INTEGER:
+			return key{kind: keyInt, i: arg.Int64()}, true
+		case sqlite3.FLOAT:
+			return key{kind: keyFloat, f: arg.Float()}, true
+		}
+	case keyText:
+		if arg.Type() == sqlite3.TEXT {
+			return key{kind: keyText, s: arg.Text()}, true
+		}
+	}
+	return key{}, false
+}
+
+// compare compares two keys of compatible kinds.
+// Integers and floats are compared as floats,
+// which is monotonic, if not exact.
+func compare(a, b key) int {
+	switch {
+	case a.kind == keyText:
+		return strings.Compare(a.s, b.s)
+	case a.kind == keyInt && b.kind == keyInt:
+		return cmp.Compare(a.i, b.i)
+	}
+	return cmp.Compare(a.float(), b.float())
+}
+
+func (k key) float() float64 {
+	if k.kind == keyInt {
+		return float64(k.i)
+	}
+	return k.f
+}
+
+func log2(n float64) float64 {
+	return math.Log2(max(n, 1))
+}
@@ -0,0 +1,324 @@
+// Package structs provides a virtual table backed by a Go slice of structs.
+//
+// Each exported field of the struct becomes a column of the virtual table.
+// Columns are named by their "db" tag, or by their field name;
+// a tag of "-" skips a field.
+// Supported field types are bools, integers, floats, strings,
+// byte slices, [time.Time], and pointers to these (nil is NULL).
+//
+// Fields tagged with the key option (e.g. `db:"id,key"`) are indexed:
+// =, IS, <, <=, > and >= constraints on them
+// are satisfied with a binary search, instead of a full table scan.
+// The index is rebuilt as needed, but only changes that go through SQL,
+// or that change the length of the slice, are detected;
+// don't modify key fields in place while the virtual table is in use.
+//
+// The rowid of each row is its index in the slice.
+package structs
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ncruces/go-sqlite3"
+)
+
+// Register registers a read-only virtual table named name,
+// that exposes the slice of structs pointed to by data.
+func Register[T any](db *sqlite3.Conn, name string, data *[]T) error {
+	t, err := newTable(data)
+	if err != nil {
+		return err
+	}
+	return sqlite3.CreateModule(db, name, nil,
+		func(db *sqlite3.Conn, _, _, _ string, _ ...string) (*table, error) {
+			err := db.DeclareVtab(t.decl)
+			return t, err
+		})
+}
+
+// RegisterWritable registers a virtual table named name,
+// that exposes the slice of structs pointed to by data,
+// and supports INSERT, UPDATE and DELETE.
+//
+// Inserted rows are appended to the slice, and updated rows are modified in place.
+// Deleted rows are removed from the slice when the transaction ends,
+// so that rowids are stable within a transaction.
+// Changes are not undone by ROLLBACK.
+func RegisterWritable[T any](db *sqlite3.Conn, name string, data *[]T) error {
+	t, err := newTable(data)
+	if err != nil {
+		return err
+	}
+	w := &writable{t}
+	return sqlite3.CreateModule(db, name, nil,
+		func(db *sqlite3.Conn, _, _, _ string, _ ...string) (*writable, error) {
+			err := db.DeclareVtab(t.decl)
+			return w, err
+		})
+}
+
+type table struct {
+	data    reflect.Value // the slice
+	columns []column
+	decl    string
+
+	deleted map[int]struct{}
+	indexes map[int]*index
+	ptr     uintptr
+	len     int
+}
+
+func newTable[T any](data *[]T) (*table, error) {
+	if data == nil {
+		return nil, fmt.Errorf("structs: nil slice pointer")
+	}
+	columns, err := parseColumns(reflect.TypeOf(data).Elem().Elem())
+	if err != nil {
+		return nil, err
+	}
+	return &table{
+		data:    reflect.ValueOf(data).Elem(),
+		columns: columns,
+		decl:    declareTable(columns),
+		deleted: map[int]struct{}{},
+	}, nil
+}
+
+const (
+	planEQ    = 1
+	planLower = 2
+	planUpper = 4
+)
+
+func (t *table) BestIndex(idx *sqlite3.IndexInfo) error {
+	n := float64(t.data.Len())
+	idx.EstimatedCost = n
+	idx.EstimatedRows = int64(n)
+
+	var best struct {
+		plan  int
+		cost  float64
+		rows  int64
+		cstrs []int
+	}
+	best.cost = n
+
+	// The rowid, and each key column, is a candidate plan.
+	for col := -1; col < len(t.columns); col++ {
+		if col >= 0 && !t.columns[col].key {
+			continue
+		}
+
+		eq, lower, upper := -1, -1, -1
+		for i, cst := range idx.Constraint {
+			if cst.Column != col || !cst.Usable {
+				continue
+			}
+			if col >= 0 && t.columns[col].keyKind == keyText &&
+				idx.Collation(i) != "BINARY" {
+				continue
+			}
+			switch cst.Op {
+			case sqlite3.INDEX_CONSTRAINT_EQ, sqlite3.INDEX_CONSTRAINT_IS:
+				eq = i
+			case sqlite3.INDEX_CONSTRAINT_GT, sqlite3.INDEX_CONSTRAINT_GE:
+				if col >= 0 {
+					lower = i
+				}
+			case sqlite3.INDEX_CONSTRAINT_LT, sqlite3.INDEX_CONSTRAINT_LE:
+				if col >= 0 {
+					upper = i
+				}
+			}
+		}
+
+		var (
+			plan  int
+			cost  float64
+			rows  int64
+			cstrs []int
+		)
+		switch {
+		case eq >= 0 && col < 0:
+			plan, cost, rows, cstrs = planEQ, 1, 1, []int{eq}
+		case eq >= 0:
+			plan, cost, rows, cstrs = planEQ, log2(n)+1, 1, []int{eq}
+		case lower >= 0 && upper >= 0:
+			plan, cost, rows, cstrs = planLower|planUpper, log2(n)+n/16, int64(n/16), []int{lower, upper}
+		case lower >= 0:
+			plan, cost, rows, cstrs = planLower, log2(n)+n/4, int64(n/4), []int{lower}
+		case upper >= 0:
+			plan, cost, rows, cstrs = planUpper, log2(n)+n/4, int64(n/4), []int{upper}
+		default:
+			continue
+		}
+		if cost < best.cost {
+			best.plan = (col+1)<<3 | plan
+			best.cost = cost
+			best.rows = rows
+			best.cstrs = cstrs
+		}
+	}
+
+	if best.plan != 0 {
+		// Constraints are not omitted:
+		// SQLite double checks them, so the plan
+		// may return a superset of matching rows.
+		for i, cst := range best.cstrs {
+			idx.ConstraintUsage[cst].ArgvIndex = i + 1
+		}
+		idx.IdxNum = best.plan
+		idx.EstimatedCost = best.cost
+		idx.EstimatedRows = best.rows
+		if best.plan>>3 == 0 {
+			idx.IdxFlags = sqlite3.INDEX_SCAN_UNIQUE
+		}
+	}
+	return nil
+}
+
+func (t *table) Open() (sqlite3.VTabCursor, error) {
+	return &cursor{table: t}, nil
+}
+
+type writable struct {
+	*table
+}
+
+func (t *writable) Update(arg ...sqlite3.Value) (rowid int64, err error) {
+	defer t.invalidate()
+
+	// DELETE
+	if len(arg) == 1 {
+		t.deleted[int(arg[0].Int64())] = struct{}{}
+		return 0, nil
+	}
+
+	// INSERT
+	if arg[0].Type() == sqlite3.NULL {
+		if arg[1].Type() != sqlite3.NULL {
+			return 0, fmt.Errorf("structs: rowid can't be set:%.0w", sqlite3.CONSTRAINT)
+		}
+		row := reflect.New(t.data.Type().Elem()).Elem()
+		if err := t.assign(row, arg[2:]); err != nil {
+			return 0, err
+		}
+		t.data.Set(reflect.Append(t.data, row))
+		return int64(t.data.Len() - 1), nil
+	}
+
+	// UPDATE
+	rowid = arg[0].Int64()
+	if arg[1].Int64() != rowid {
+		return 0, fmt.Errorf("structs: rowid can't be changed:%.0w", sqlite3.CONSTRAINT)
+	}
+	row := reflect.New(t.data.Type().Elem()).Elem()
+	row.Set(t.data.Index(int(rowid)))
+	if err := t.assign(row, arg[2:]); err != nil {
+		return 0, err
+	}
+	t.data.Index(int(rowid)).Set(row)
+	return rowid, nil
+}
+
+func (t *table) assign(row reflect.Value, arg []sqlite3.Value) error {
+	for i, col := range t.columns {
+		if arg[i].NoChange() {
+			continue
+		}
+		if err := assign(row.Field(col.field), arg[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *writable) Begin() error { return nil }
+
+func (t *writable) Sync() error { return nil }
+
+func (t *writable) Commit() error {
+	t.compact()
+	return nil
+}
+
+func (t *writable) Rollback() error {
+	t.compact()
+	return nil
+}
+
+// compact removes deleted rows from the slice.
+func (t *table) compact() {
+	if len(t.deleted) == 0 {
+		return
+	}
+	defer t.invalidate()
+
+	n := 0
+	for i, l := 0, t.data.Len(); i < l; i++ {
+		if _, ok := t.deleted[i]; ok {
+			continue
+		}
+		if n != i {
+			t.data.Index(n).Set(t.data.Index(i))
+		}
+		n++
+	}
+	// Clear the tail, so its contents can be collected.
+	for i, l := n, t.data.Len(); i < l; i++ {
+		t.data.Index(i).SetZero()
+	}
+	t.data.SetLen(n)
+	clear(t.deleted)
+}
+
+type cursor struct {
+	table *table
+	rows  []int
+	pos   int
+}
+
+func (c *cursor) Filter(idxNum int, idxStr string, arg ...sqlite3.Value) error {
+	c.pos = 0
+	c.rows = c.rows[:0]
+
+	if idxNum != 0 {
+		if rows, ok := c.table.search(idxNum, arg); ok {
+			for _, i := range rows {
+				if _, ok := c.table.deleted[i]; !ok {
+					c.rows = append(c.rows, i)
+				}
+			}
+			return nil
+		}
+	}
+
+	for i, n := 0, c.table.data.Len(); i < n; i++ {
+		if _, ok := c.table.deleted[i]; !ok {
+			c.rows = append(c.rows, i)
+		}
+	}
+	return nil
+}
+
+func (c *cursor) Column(ctx *sqlite3.Context, n int) error {
+	if ctx.VTabNoChange() {
+		return nil
+	}
+	row := c.table.data.Index(c.rows[c.pos])
+	return result(ctx, row.Field(c.table.columns[n].field))
+}
+
+func (c *cursor) Next() error {
+	c.pos++
+	return nil
+}
+
+func (c *cursor) EOF() bool {
+	return c.pos >= len(c.rows)
+}
+
+func (c *cursor) RowID() (int64, error) {
+	return int64(c.rows[c.pos]), nil
+}
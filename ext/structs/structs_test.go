@@ -0,0 +1,194 @@
+package structs_test
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"testing"
+
+	"github.com/ncruces/go-sqlite3"
+	_ "github.com/ncruces/go-sqlite3/embed"
+	"github.com/ncruces/go-sqlite3/ext/structs"
+)
+
+type person struct {
+	ID    int64  `db:"id,key"`
+	Name  string `db:"name,key"`
+	Email *string
+	notes string
+}
+
+func Example() {
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	people := []person{
+		{ID: 1, Name: "Alice"},
+		{ID: 2, Name: "Bob"},
+		{ID: 3, Name: "Carol"},
+	}
+
+	err = structs.Register(db, "people", &people)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	stmt, _, err := db.Prepare(`SELECT name FROM people WHERE id >= 2 ORDER BY id`)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer stmt.Close()
+
+	for stmt.Step() {
+		fmt.Println(stmt.ColumnText(0))
+	}
+	if err := stmt.Err(); err != nil {
+		log.Fatal(err)
+	}
+	// Output:
+	// Bob
+	// Carol
+}
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var people []person
+	for i := 0; i < 100; i++ {
+		people = append(people, person{ID: int64(i % 10), Name: fmt.Sprint("p", i)})
+	}
+
+	err = structs.Register(db, "people", &people)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{`SELECT count(*) FROM people`, 100},
+		{`SELECT count(*) FROM people WHERE id = 3`, 10},
+		{`SELECT count(*) FROM people WHERE id IS 3`, 10},
+		{`SELECT count(*) FROM people WHERE id = 3.0`, 10},
+		{`SELECT count(*) FROM people WHERE id = 3.5`, 0},
+		{`SELECT count(*) FROM people WHERE id > 7`, 20},
+		{`SELECT count(*) FROM people WHERE id > 7.5`, 20},
+		{`SELECT count(*) FROM people WHERE id < 2.5`, 30},
+		{`SELECT count(*) FROM people WHERE id BETWEEN 2 AND 4`, 30},
+		{`SELECT count(*) FROM people WHERE id > 4 AND id < 2`, 0},
+		{`SELECT count(*) FROM people WHERE id = '3'`, 10},
+		{`SELECT count(*) FROM people WHERE name = 'p42'`, 1},
+		{`SELECT count(*) FROM people WHERE name = 'P42' COLLATE NOCASE`, 1},
+		{`SELECT count(*) FROM people WHERE name >= 'p9'`, 11},
+		{`SELECT count(*) FROM people WHERE email IS NULL`, 100},
+		{`SELECT count(*) FROM people WHERE rowid = 42`, 1},
+		{`SELECT count(*) FROM people WHERE rowid = 420`, 0},
+	}
+	for _, tt := range tests {
+		stmt, _, err := db.Prepare(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !stmt.Step() {
+			t.Fatal(stmt.Err())
+		}
+		if got := stmt.ColumnInt(0); got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.query, got, tt.want)
+		}
+		stmt.Close()
+	}
+
+	err = db.Exec(`DELETE FROM people`)
+	if err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestRegisterWritable(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	people := []person{
+		{ID: 1, Name: "Alice"},
+		{ID: 2, Name: "Bob"},
+		{ID: 3, Name: "Carol"},
+	}
+
+	err = structs.RegisterWritable(db, "people", &people)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Exec(`
+		BEGIN;
+		INSERT INTO people (id, name, email) VALUES (4, 'Dave', 'dave@example.com');
+		UPDATE people SET name = 'Robert' WHERE id = 2;
+		DELETE FROM people WHERE id IN (1, 3);
+		UPDATE people SET email = 'bob@example.com' WHERE name = 'Robert';
+		COMMIT;
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bob := "bob@example.com"
+	dave := "dave@example.com"
+	want := []person{
+		{ID: 2, Name: "Robert", Email: &bob},
+		{ID: 4, Name: "Dave", Email: &dave},
+	}
+	if !reflect.DeepEqual(people, want) {
+		t.Errorf("got %v", people)
+	}
+
+	err = db.Exec(`INSERT INTO people (rowid, id) VALUES (10, 10)`)
+	if err == nil {
+		t.Error("want error")
+	}
+	err = db.Exec(`UPDATE people SET rowid = 10`)
+	if err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestRegister_errors(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var ints []int
+	if err := structs.Register(db, "ints", &ints); err == nil {
+		t.Error("want error")
+	}
+
+	var chans []struct{ C chan int }
+	if err := structs.Register(db, "chans", &chans); err == nil {
+		t.Error("want error")
+	}
+
+	var blobs []struct {
+		B []byte `db:",key"`
+	}
+	if err := structs.Register(db, "blobs", &blobs); err == nil {
+		t.Error("want error")
+	}
+}
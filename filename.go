@@ -0,0 +1,105 @@
+package sqlite3
+
+import (
+	"net/url"
+
+	"github.com/ncruces/go-sqlite3/internal/util"
+)
+
+// Filename is a database filename pointer,
+// which is only valid while the connection that returned it is open.
+//
+// https://sqlite.org/c3ref/filename.html
+type Filename struct {
+	c      *Conn
+	handle uint32
+}
+
+// Filename returns the filename for a database connection schema.
+// If schema is empty, the filename of the main database is returned.
+// It returns nil if there is no such attached database.
+//
+// For temporary and in-memory databases, all paths are empty.
+//
+// https://sqlite.org/c3ref/db_filename.html
+func (c *Conn) Filename(schema string) *Filename {
+	defer c.arena.mark()()
+	var schemaPtr uint32
+	if schema != "" {
+		schemaPtr = c.arena.string(schema)
+	}
+
+	r := c.call("sqlite3_db_filename", uint64(c.handle), uint64(schemaPtr))
+	if r == 0 {
+		return nil
+	}
+	return &Filename{c, uint32(r)}
+}
+
+// Database returns the path of the database file.
+//
+// https://sqlite.org/c3ref/filename_database.html
+func (n *Filename) Database() string {
+	return n.path("sqlite3_filename_database")
+}
+
+// Journal returns the path of the rollback journal file.
+//
+// https://sqlite.org/c3ref/filename_database.html
+func (n *Filename) Journal() string {
+	return n.path("sqlite3_filename_journal")
+}
+
+// WAL returns the path of the WAL file.
+//
+// https://sqlite.org/c3ref/filename_database.html
+func (n *Filename) WAL() string {
+	return n.path("sqlite3_filename_wal")
+}
+
+func (n *Filename) path(call string) string {
+	r := n.c.call(call, uint64(n.handle))
+	if r == 0 {
+		return ""
+	}
+	return util.ReadString(n.c.mod, uint32(r), _MAX_PATHNAME)
+}
+
+// URIParameter returns the value of a URI parameter,
+// or an empty string if the parameter is not present.
+//
+// https://sqlite.org/c3ref/uri_boolean.html
+func (n *Filename) URIParameter(key string) string {
+	defer n.c.arena.mark()()
+	keyPtr := n.c.arena.string(key)
+
+	r := n.c.call("sqlite3_uri_parameter", uint64(n.handle), uint64(keyPtr))
+	if r == 0 {
+		return ""
+	}
+	return util.ReadString(n.c.mod, uint32(r), _MAX_NAME)
+}
+
+// URIParameters returns all URI parameters.
+// For repeated parameters, only the first value is returned.
+//
+// https://sqlite.org/c3ref/uri_boolean.html
+func (n *Filename) URIParameters() url.Values {
+	var params url.Values
+	for i := 0; ; i++ {
+		r := n.c.call("sqlite3_uri_key", uint64(n.handle), uint64(i))
+		if r == 0 {
+			return params
+		}
+		key := util.ReadString(n.c.mod, uint32(r), _MAX_NAME)
+		if params.Has(key) {
+			continue
+		}
+
+		r = n.c.call("sqlite3_uri_parameter", uint64(n.handle), r)
+		if params == nil {
+			params = url.Values{}
+		}
+		params.Set(key, util.ReadString(n.c.mod, uint32(r), _MAX_NAME))
+	}
+}
@@ -0,0 +1,301 @@
+package sqlite3
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ncruces/go-sqlite3/internal/util"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// CommitHook registers a callback function to be invoked
+// whenever a transaction is committed.
+// Return true to allow the commit operation to continue normally;
+// return false to convert the commit into a rollback.
+// A nil callback unregisters the hook.
+//
+// https://sqlite.org/c3ref/commit_hook.html
+func (c *Conn) CommitHook(cb func() (ok bool)) {
+	var enable uint64
+	if cb != nil {
+		enable = 1
+	}
+	c.call("sqlite3_commit_hook_go", uint64(c.handle), enable)
+	c.commit = cb
+}
+
+// RollbackHook registers a callback function to be invoked
+// whenever a transaction is rolled back.
+// A nil callback unregisters the hook.
+//
+// https://sqlite.org/c3ref/commit_hook.html
+func (c *Conn) RollbackHook(cb func()) {
+	var enable uint64
+	if cb != nil {
+		enable = 1
+	}
+	c.call("sqlite3_rollback_hook_go", uint64(c.handle), enable)
+	c.rollback = cb
+}
+
+// UpdateHook registers a callback function to be invoked
+// whenever a row is updated, inserted or deleted in a rowid table.
+// A nil callback unregisters the hook.
+//
+// https://sqlite.org/c3ref/update_hook.html
+func (c *Conn) UpdateHook(cb func(action AuthorizerActionCode, schema, table string, rowid int64)) {
+	var enable uint64
+	if cb != nil {
+		enable = 1
+	}
+	c.call("sqlite3_update_hook_go", uint64(c.handle), enable)
+	c.update = cb
+}
+
+// PreUpdateHook registers a callback function to be invoked
+// prior to each INSERT, UPDATE, and DELETE operation on a database table.
+// A nil callback unregisters the hook.
+//
+// https://sqlite.org/c3ref/preupdate_blobwrite.html
+func (c *Conn) PreUpdateHook(cb func(PreUpdate)) {
+	var enable uint64
+	if cb != nil {
+		enable = 1
+	}
+	c.call("sqlite3_preupdate_hook_go", uint64(c.handle), enable)
+	c.preupdate = cb
+}
+
+// PreUpdate describes a change about to be made to a table
+// through the callback registered with [Conn.PreUpdateHook].
+// A PreUpdate is only valid for the duration of the callback.
+//
+// https://sqlite.org/c3ref/preupdate_blobwrite.html
+type PreUpdate struct {
+	c *Conn
+
+	// Op is one of [AUTH_INSERT], [AUTH_DELETE] or [AUTH_UPDATE].
+	Op     AuthorizerActionCode
+	Schema string
+	Table  string
+	// OldRowID is the rowid of the row being deleted or updated.
+	// It is undefined for INSERT operations and WITHOUT ROWID tables.
+	OldRowID int64
+	// NewRowID is the rowid of the row being inserted or updated.
+	// It is undefined for DELETE operations and WITHOUT ROWID tables.
+	NewRowID int64
+}
+
+// Old returns the value of column col of the table row before it is updated.
+// It may only be used with DELETE and UPDATE operations.
+// The leftmost column of the table has the index 0.
+//
+// https://sqlite.org/c3ref/preupdate_blobwrite.html
+func (p PreUpdate) Old(col int) (Value, error) {
+	return p.value("sqlite3_preupdate_old", col)
+}
+
+// New returns the value of column col of the table row after it is updated.
+// It may only be used with INSERT and UPDATE operations.
+// The leftmost column of the table has the index 0.
+//
+// https://sqlite.org/c3ref/preupdate_blobwrite.html
+func (p PreUpdate) New(col int) (Value, error) {
+	return p.value("sqlite3_preupdate_new", col)
+}
+
+func (p PreUpdate) value(call string, col int) (Value, error) {
+	defer p.c.arena.mark()()
+	valPtr := p.c.arena.new(ptrlen)
+	r := p.c.call(call, uint64(p.c.handle), uint64(col), uint64(valPtr))
+	if err := p.c.error(r); err != nil {
+		return Value{}, err
+	}
+	return Value{
+		sqlite: p.c.sqlite,
+		handle: util.ReadUint32(p.c.mod, valPtr),
+	}, nil
+}
+
+// Count returns the number of columns in the row
+// that is being inserted, updated, or deleted.
+//
+// https://sqlite.org/c3ref/preupdate_blobwrite.html
+func (p PreUpdate) Count() int {
+	r := p.c.call("sqlite3_preupdate_count", uint64(p.c.handle))
+	return int(int32(r))
+}
+
+// Depth returns 0 if the change is caused by a top-level SQL statement,
+// 1 if it is caused by a top-level trigger, 2 if it is caused by
+// a trigger fired by a top-level trigger, and so on.
+//
+// https://sqlite.org/c3ref/preupdate_blobwrite.html
+func (p PreUpdate) Depth() int {
+	r := p.c.call("sqlite3_preupdate_depth", uint64(p.c.handle))
+	return int(int32(r))
+}
+
+func commitCallback(ctx context.Context, mod api.Module, pDB uint32) (rollback uint32) {
+	if c, ok := ctx.Value(connKey{}).(*Conn); ok && c.handle == pDB && c.commit != nil {
+		if !c.commit() {
+			rollback = 1
+		}
+	}
+	return rollback
+}
+
+func rollbackCallback(ctx context.Context, mod api.Module, pDB uint32) {
+	if c, ok := ctx.Value(connKey{}).(*Conn); ok && c.handle == pDB && c.rollback != nil {
+		c.rollback()
+	}
+}
+
+func preUpdateCallback(ctx context.Context, mod api.Module, _, pDB uint32, op AuthorizerActionCode, zSchema, zTabName uint32, iKey1, iKey2 int64) {
+	if c, ok := ctx.Value(connKey{}).(*Conn); ok && c.handle == pDB && c.preupdate != nil {
+		c.preupdate(PreUpdate{
+			c:        c,
+			Op:       op,
+			Schema:   util.ReadString(mod, zSchema, _MAX_NAME),
+			Table:    util.ReadString(mod, zTabName, _MAX_NAME),
+			OldRowID: iKey1,
+			NewRowID: iKey2,
+		})
+	}
+}
+
+func updateCallback(ctx context.Context, mod api.Module, pDB uint32, action AuthorizerActionCode, zSchema, zTabName uint32, rowid uint64) {
+	if c, ok := ctx.Value(connKey{}).(*Conn); ok && c.handle == pDB && c.update != nil {
+		schema := util.ReadString(mod, zSchema, _MAX_NAME)
+		table := util.ReadString(mod, zTabName, _MAX_NAME)
+		c.update(action, schema, table, int64(rowid))
+	}
+}
+
+// SetAuthorizer registers an authorizer callback with the database connection.
+// The callback is invoked as SQL statements are being compiled by [Conn.Prepare].
+// If the callback returns [AUTH_DENY], the statement fails to compile with [AUTH].
+// A nil callback removes the authorizer.
+//
+// https://sqlite.org/c3ref/set_authorizer.html
+func (c *Conn) SetAuthorizer(cb func(action AuthorizerActionCode, name3, name4, schema, trigger string) AuthorizerReturnCode) error {
+	var enable uint64
+	if cb != nil {
+		enable = 1
+	}
+	r := c.call("sqlite3_set_authorizer_go", uint64(c.handle), enable)
+	if err := c.error(r); err != nil {
+		return err
+	}
+	c.authorizer = cb
+	return nil
+}
+
+func authorizerCallback(ctx context.Context, mod api.Module, pDB uint32, action AuthorizerActionCode, zName3, zName4, zSchema, zTrigger uint32) (rc AuthorizerReturnCode) {
+	if c, ok := ctx.Value(connKey{}).(*Conn); ok && c.handle == pDB && c.authorizer != nil {
+		var name3, name4, schema, trigger string
+		if zName3 != 0 {
+			name3 = util.ReadString(mod, zName3, _MAX_NAME)
+		}
+		if zName4 != 0 {
+			name4 = util.ReadString(mod, zName4, _MAX_NAME)
+		}
+		if zSchema != 0 {
+			schema = util.ReadString(mod, zSchema, _MAX_NAME)
+		}
+		if zTrigger != 0 {
+			trigger = util.ReadString(mod, zTrigger, _MAX_NAME)
+		}
+		rc = c.authorizer(action, name3, name4, schema, trigger)
+	}
+	return rc
+}
+
+// Trace registers a trace callback function against the database connection.
+// The mask selects which events invoke the callback;
+// a zero mask, or a nil callback, disables tracing.
+//
+// The arguments passed to the callback depend on the event:
+//   - [TRACE_STMT]: arg1 is the [*Stmt], arg2 is its expanded SQL text,
+//     or a comment identifying the trigger being run (e.g. "-- TRIGGER name");
+//   - [TRACE_PROFILE]: arg1 is the [*Stmt], arg2 is its run time as a [time.Duration];
+//   - [TRACE_ROW]: arg1 is the [*Stmt], arg2 is nil;
+//   - [TRACE_CLOSE]: arg1 is the [*Conn], arg2 is nil.
+//
+// Statements that were not prepared with [Conn.Prepare]
+// (e.g. those run by [Conn.Exec]) are also passed as a [*Stmt],
+// which is only valid for the duration of the callback,
+// and must not be closed.
+//
+// Errors returned by the callback are currently ignored by SQLite.
+//
+// https://sqlite.org/c3ref/trace_v2.html
+func (c *Conn) Trace(mask TraceEvent, cb func(evt TraceEvent, arg1 any, arg2 any) error) error {
+	if cb == nil {
+		mask = 0
+	}
+	r := c.call("sqlite3_trace_go", uint64(c.handle), uint64(mask))
+	if err := c.error(r); err != nil {
+		return err
+	}
+	c.trace = cb
+	return nil
+}
+
+func traceCallback(ctx context.Context, mod api.Module, evt TraceEvent, pDB, pArg1, pArg2 uint32) (rc uint32) {
+	if c, ok := ctx.Value(connKey{}).(*Conn); ok && c.handle == pDB && c.trace != nil {
+		var arg1, arg2 any
+		if evt == TRACE_CLOSE {
+			arg1 = c
+		} else {
+			stmt := c.traceStmt(pArg1)
+			arg1 = stmt
+			switch evt {
+			case TRACE_STMT:
+				sql := util.ReadString(mod, pArg2, _MAX_SQL_LENGTH)
+				if !strings.HasPrefix(sql, "--") {
+					sql = stmt.ExpandedSQL()
+				}
+				arg2 = sql
+			case TRACE_PROFILE:
+				arg2 = time.Duration(util.ReadUint64(mod, pArg2))
+			}
+		}
+		_, rc = errorCode(c.trace(evt, arg1, arg2), ERROR)
+	}
+	return rc
+}
+
+func (c *Conn) traceStmt(handle uint32) *Stmt {
+	if s, ok := c.stmts[handle]; ok {
+		return s
+	}
+	return &Stmt{c: c, handle: handle}
+}
+
+// WALHook registers a callback function to be invoked
+// each time data is committed to a database in WAL mode.
+// The callback is passed the number of pages currently in the WAL file.
+// A nil callback unregisters the hook.
+//
+// WALHook replaces auto-checkpoints configured with [Conn.WALAutoCheckpoint].
+//
+// https://sqlite.org/c3ref/wal_hook.html
+func (c *Conn) WALHook(cb func(db *Conn, schema string, pages int) error) {
+	var enable uint64
+	if cb != nil {
+		enable = 1
+	}
+	c.call("sqlite3_wal_hook_go", uint64(c.handle), enable)
+	c.wal = cb
+}
+
+func walCallback(ctx context.Context, mod api.Module, _, pDB, zSchema uint32, pages int32) (rc uint32) {
+	if c, ok := ctx.Value(connKey{}).(*Conn); ok && c.handle == pDB && c.wal != nil {
+		schema := util.ReadString(mod, zSchema, _MAX_NAME)
+		err := c.wal(c, schema, int(pages))
+		_, rc = errorCode(err, ERROR)
+	}
+	return rc
+}
@@ -0,0 +1,296 @@
+package sqlite3
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ncruces/go-sqlite3/internal/util"
+)
+
+// QueryAll executes a query and returns all resulting rows as a slice of T.
+//
+// If T is a struct, columns are mapped to exported fields by name,
+// as described in [Exec]; otherwise, the query must return a single column,
+// which is scanned into T as described in [Stmt.Scan].
+//
+// Arguments are bound as described in [Exec].
+func QueryAll[T any](c *Conn, query string, args ...any) ([]T, error) {
+	s, err := prepareArgs(c, query, args)
+	if err != nil || s == nil {
+		return nil, err
+	}
+	defer s.Close()
+
+	var res []T
+	var scan rowScanner
+	for s.Step() {
+		var row T
+		if err := scan.scan(s, &row); err != nil {
+			return nil, err
+		}
+		res = append(res, row)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// QueryOne is like [QueryAll], but returns only the first row.
+// If the query returns no rows, QueryOne returns [sql.ErrNoRows].
+func QueryOne[T any](c *Conn, query string, args ...any) (row T, err error) {
+	s, err := prepareArgs(c, query, args)
+	if err != nil {
+		return row, err
+	}
+	if s == nil {
+		return row, sql.ErrNoRows
+	}
+	defer s.Close()
+
+	if !s.Step() {
+		if err = s.Err(); err == nil {
+			err = sql.ErrNoRows
+		}
+		return row, err
+	}
+
+	var scan rowScanner
+	err = scan.scan(s, &row)
+	return row, err
+}
+
+// Exec executes a single statement with arguments.
+//
+// If a single argument is a struct, or a pointer to a struct,
+// named parameters (:name, @name or $name) are bound from its fields.
+// Fields are named by their "db" tag (up to the first comma),
+// or by their field name, case-insensitively; a tag of "-" skips a field,
+// and the fields of embedded structs are promoted.
+// If a single argument is a map[string]any, it is bound with [Stmt.BindNamed].
+// Otherwise, arguments are bound with [Stmt.Bind].
+func Exec(c *Conn, query string, args ...any) error {
+	s, err := prepareArgs(c, query, args)
+	if err != nil || s == nil {
+		return err
+	}
+	defer s.Close()
+	return s.Exec()
+}
+
+func prepareArgs(c *Conn, query string, args []any) (*Stmt, error) {
+	s, tail, err := c.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	if tail != "" {
+		s.Close()
+		return nil, util.TailErr
+	}
+	if s == nil {
+		return nil, nil
+	}
+
+	if err := bindArgs(s, args); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func bindArgs(s *Stmt, args []any) error {
+	if len(args) == 1 {
+		switch a := args[0].(type) {
+		case map[string]any:
+			return s.BindNamed(a)
+		}
+		if v, info := structOf(args[0]); info != nil {
+			return bindStruct(s, v, info)
+		}
+	}
+	return s.Bind(args...)
+}
+
+func bindStruct(s *Stmt, v reflect.Value, info *structInfo) error {
+	for i, n := 1, s.BindCount(); i <= n; i++ {
+		name := s.BindName(i)
+		if name == "" {
+			return fmt.Errorf("sqlite3: unnamed parameter %d", i)
+		}
+		index, ok := info.fields[strings.ToLower(name[1:])]
+		if !ok {
+			return fmt.Errorf("sqlite3: no field for parameter %s", name)
+		}
+		var arg any
+		if f, ok := fieldByIndex(v, index, false); ok {
+			arg = f.Interface()
+		}
+		if err := s.BindAny(i, arg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type rowScanner struct {
+	index [][]int
+	dest  []any
+}
+
+func (r *rowScanner) scan(s *Stmt, row any) error {
+	v := reflect.ValueOf(row).Elem()
+	info := structInfoOf(v.Type())
+	if info == nil {
+		return s.Scan(row)
+	}
+
+	if r.index == nil {
+		n := s.ColumnCount()
+		r.index = make([][]int, n)
+		r.dest = make([]any, n)
+		for i := range r.index {
+			name := s.ColumnName(i)
+			index, ok := info.fields[strings.ToLower(name)]
+			if !ok {
+				return fmt.Errorf("sqlite3: no field for column %s", name)
+			}
+			r.index[i] = index
+		}
+	}
+
+	for i, index := range r.index {
+		f, _ := fieldByIndex(v, index, true)
+		r.dest[i] = f.Addr().Interface()
+	}
+	return s.Scan(r.dest...)
+}
+
+type structInfo struct {
+	fields map[string][]int // lowercase name to field index
+}
+
+var structInfos sync.Map // map[reflect.Type]*structInfo
+
+// structOf returns the struct value of arg, and its metadata,
+// if arg is a struct, or a non-nil pointer to a struct,
+// that is not itself a bindable value.
+func structOf(arg any) (reflect.Value, *structInfo) {
+	v := reflect.ValueOf(arg)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return v, nil
+		}
+		v = v.Elem()
+	}
+	return v, structInfoOf(v.Type())
+}
+
+// structInfoOf returns the metadata of a struct type,
+// or nil if typ is not a struct, or is a struct with its own
+// binding or scanning behavior.
+func structInfoOf(typ reflect.Type) *structInfo {
+	if typ.Kind() != reflect.Struct || isValueType(typ) {
+		return nil
+	}
+	if info, ok := structInfos.Load(typ); ok {
+		return info.(*structInfo)
+	}
+
+	info := &structInfo{fields: map[string][]int{}}
+	depths := map[string]int{}
+	var walk func(typ reflect.Type, index []int)
+	walk = func(typ reflect.Type, index []int) {
+		for i := 0; i < typ.NumField(); i++ {
+			f := typ.Field(i)
+			tag, _, _ := strings.Cut(f.Tag.Get("db"), ",")
+			if tag == "-" {
+				continue
+			}
+
+			idx := append(index[:len(index):len(index)], i)
+			if f.Anonymous && tag == "" {
+				t := f.Type
+				if t.Kind() == reflect.Pointer {
+					// Like encoding/json, skip pointers to unexported
+					// embedded structs: they can't be allocated.
+					if !f.IsExported() {
+						continue
+					}
+					t = t.Elem()
+				}
+				if t.Kind() == reflect.Struct && !isValueType(t) {
+					walk(t, idx)
+					continue
+				}
+			}
+			if !f.IsExported() {
+				continue
+			}
+
+			name := tag
+			if name == "" {
+				name = f.Name
+			}
+			name = strings.ToLower(name)
+
+			// Shallower fields shadow deeper ones.
+			if d, ok := depths[name]; ok && d <= len(idx) {
+				continue
+			}
+			depths[name] = len(idx)
+			info.fields[name] = idx
+		}
+	}
+	walk(typ, nil)
+
+	actual, _ := structInfos.LoadOrStore(typ, info)
+	return actual.(*structInfo)
+}
+
+var (
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	pointerType = reflect.TypeOf((*interface{ Pointer() any })(nil)).Elem()
+	jsonType    = reflect.TypeOf((*interface{ JSON() any })(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+	valueType   = reflect.TypeOf(Value{})
+)
+
+// isValueType reports whether a struct type
+// is bound or scanned as a single value.
+func isValueType(typ reflect.Type) bool {
+	if typ == timeType || typ == valueType {
+		return true
+	}
+	for _, t := range [...]reflect.Type{typ, reflect.PointerTo(typ)} {
+		if t.Implements(valuerType) || t.Implements(scannerType) ||
+			t.Implements(pointerType) || t.Implements(jsonType) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldByIndex is like [reflect.Value.FieldByIndex],
+// but allocates nil embedded pointers if alloc is true,
+// and otherwise reports false when it finds one.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
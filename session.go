@@ -0,0 +1,668 @@
+package sqlite3
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/ncruces/go-sqlite3/internal/util"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// Session is a session object that records changes to a database.
+//
+// https://sqlite.org/session/session.html
+type Session struct {
+	c      *Conn
+	handle uint32
+}
+
+// CreateSession creates a new session object
+// that records changes to the database schema of the connection.
+// If schema is empty, changes to the main database are recorded.
+//
+// https://sqlite.org/session/sqlite3session_create.html
+func (c *Conn) CreateSession(schema string) (*Session, error) {
+	if schema == "" {
+		schema = "main"
+	}
+
+	defer c.arena.mark()()
+	sessionPtr := c.arena.new(ptrlen)
+	schemaPtr := c.arena.string(schema)
+
+	r := c.call("sqlite3session_create", uint64(c.handle),
+		uint64(schemaPtr), uint64(sessionPtr))
+	if err := c.error(r); err != nil {
+		return nil, err
+	}
+
+	session := Session{c: c}
+	session.handle = util.ReadUint32(c.mod, sessionPtr)
+	return &session, nil
+}
+
+// Close deletes the session object.
+// Close must be called before closing the connection the session was created on.
+//
+// It is safe to close a nil, zero or closed Session.
+//
+// https://sqlite.org/session/sqlite3session_delete.html
+func (s *Session) Close() error {
+	if s == nil || s.handle == 0 {
+		return nil
+	}
+
+	s.c.call("sqlite3session_delete", uint64(s.handle))
+
+	s.handle = 0
+	return nil
+}
+
+// Attach attaches a table to the session object.
+// Changes made to the table after it is attached are recorded.
+// If table is empty, changes to all tables are recorded.
+//
+// https://sqlite.org/session/sqlite3session_attach.html
+func (s *Session) Attach(table string) error {
+	defer s.c.arena.mark()()
+	var tablePtr uint32
+	if table != "" {
+		tablePtr = s.c.arena.string(table)
+	}
+	r := s.c.call("sqlite3session_attach", uint64(s.handle), uint64(tablePtr))
+	return s.c.error(r)
+}
+
+// Changeset generates a changeset from the changes recorded by the session.
+//
+// https://sqlite.org/session/sqlite3session_changeset.html
+func (s *Session) Changeset() ([]byte, error) {
+	return s.output("sqlite3session_changeset")
+}
+
+// Patchset generates a patchset from the changes recorded by the session.
+// Patchsets are a more compact form of changesets
+// that cannot be inverted, and that have more limited conflict detection.
+//
+// https://sqlite.org/session/sqlite3session_patchset.html
+func (s *Session) Patchset() ([]byte, error) {
+	return s.output("sqlite3session_patchset")
+}
+
+// ChangesetStream is like [Session.Changeset],
+// but writes the changeset to w.
+//
+// https://sqlite.org/session/sqlite3session_changeset_strm.html
+func (s *Session) ChangesetStream(w io.Writer) error {
+	return s.outputStream("sqlite3session_changeset_strm_go", w)
+}
+
+// PatchsetStream is like [Session.Patchset],
+// but writes the patchset to w.
+//
+// https://sqlite.org/session/sqlite3session_changeset_strm.html
+func (s *Session) PatchsetStream(w io.Writer) error {
+	return s.outputStream("sqlite3session_patchset_strm_go", w)
+}
+
+func (s *Session) outputStream(call string, w io.Writer) error {
+	out := util.AddHandle(s.c.ctx, strmWriter{w})
+	defer util.DelHandle(s.c.ctx, out)
+
+	r := s.c.call(call, uint64(s.handle), uint64(out))
+	return s.c.error(r)
+}
+
+func (s *Session) output(call string) ([]byte, error) {
+	defer s.c.arena.mark()()
+	nPtr := s.c.arena.new(ptrlen)
+	dataPtr := s.c.arena.new(ptrlen)
+
+	r := s.c.call(call, uint64(s.handle), uint64(nPtr), uint64(dataPtr))
+	if err := s.c.error(r); err != nil {
+		return nil, err
+	}
+	return s.c.output(nPtr, dataPtr), nil
+}
+
+// output copies a buffer returned by SQLite into Go memory, and frees it.
+func (c *Conn) output(nPtr, dataPtr uint32) []byte {
+	n := util.ReadUint32(c.mod, nPtr)
+	ptr := util.ReadUint32(c.mod, dataPtr)
+	if ptr == 0 {
+		return nil
+	}
+	data := make([]byte, n)
+	copy(data, util.View(c.mod, ptr, uint64(n)))
+	c.call("sqlite3_free", uint64(ptr))
+	return data
+}
+
+// ApplyChangeset applies a changeset or patchset to the database.
+//
+// If filter is not nil, it is invoked with the name of each table in the changeset,
+// and changes to the table are skipped unless it returns true.
+//
+// The conflict handler is invoked for each change that can't be applied cleanly,
+// and decides whether to omit the change, replace the conflicting row, or abort.
+// A nil conflict handler aborts on any conflict.
+//
+// https://sqlite.org/session/sqlite3changeset_apply.html
+func (c *Conn) ApplyChangeset(data []byte, filter func(table string) bool, conflict func(ConflictKind, ChangesetIterator) ConflictResolution) error {
+	_, err := c.applyChangeset(data, false, filter, conflict)
+	return err
+}
+
+// ApplyChangesetRebase is like [Conn.ApplyChangeset],
+// but also returns a buffer that can be used to configure a [Rebaser].
+//
+// https://sqlite.org/session/sqlite3changeset_apply.html
+func (c *Conn) ApplyChangesetRebase(data []byte, filter func(table string) bool, conflict func(ConflictKind, ChangesetIterator) ConflictResolution) (rebase []byte, err error) {
+	return c.applyChangeset(data, true, filter, conflict)
+}
+
+func (c *Conn) applyChangeset(data []byte, rebase bool, filter func(table string) bool, conflict func(ConflictKind, ChangesetIterator) ConflictResolution) ([]byte, error) {
+	defer c.arena.mark()()
+	var nPtr, rebasePtr uint32
+	if rebase {
+		nPtr = c.arena.new(ptrlen)
+		rebasePtr = c.arena.new(ptrlen)
+		util.WriteUint32(c.mod, rebasePtr, 0)
+	}
+
+	var enable uint64
+	if filter != nil {
+		enable = 1
+	}
+
+	dataPtr := c.newBytes(data)
+	defer c.free(dataPtr)
+
+	handle := util.AddHandle(c.ctx, &applyHandlers{filter, conflict})
+	defer util.DelHandle(c.ctx, handle)
+
+	r := c.call("sqlite3changeset_apply_go", uint64(c.handle),
+		uint64(len(data)), uint64(dataPtr),
+		uint64(nPtr), uint64(rebasePtr), enable, uint64(handle))
+	if rebase {
+		data = c.output(nPtr, rebasePtr)
+	} else {
+		data = nil
+	}
+	if err := c.error(r); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// ApplyChangesetStream is like [Conn.ApplyChangeset],
+// but reads the changeset or patchset from r.
+//
+// https://sqlite.org/session/sqlite3changeset_apply_strm.html
+func (c *Conn) ApplyChangesetStream(r io.Reader, filter func(table string) bool, conflict func(ConflictKind, ChangesetIterator) ConflictResolution) error {
+	var enable uint64
+	if filter != nil {
+		enable = 1
+	}
+
+	in := util.AddHandle(c.ctx, strmReader{r})
+	defer util.DelHandle(c.ctx, in)
+
+	handle := util.AddHandle(c.ctx, &applyHandlers{filter, conflict})
+	defer util.DelHandle(c.ctx, handle)
+
+	rc := c.call("sqlite3changeset_apply_strm_go", uint64(c.handle),
+		uint64(in), enable, uint64(handle))
+	return c.error(rc)
+}
+
+type applyHandlers struct {
+	filter   func(table string) bool
+	conflict func(ConflictKind, ChangesetIterator) ConflictResolution
+}
+
+// ChangesetIterator iterates over the changes in a changeset.
+//
+// https://sqlite.org/session/changeset_iter.html
+type ChangesetIterator struct {
+	c      *Conn
+	err    error
+	handle uint32
+	data   uint32
+	strm   uint32
+}
+
+// IterateChangeset creates an iterator over the changes in a changeset or patchset.
+// The iterator must be closed with [ChangesetIterator.Close].
+//
+// https://sqlite.org/session/sqlite3changeset_start.html
+func (c *Conn) IterateChangeset(data []byte) (*ChangesetIterator, error) {
+	defer c.arena.mark()()
+	iterPtr := c.arena.new(ptrlen)
+	dataPtr := c.newBytes(data)
+
+	r := c.call("sqlite3changeset_start", uint64(iterPtr),
+		uint64(len(data)), uint64(dataPtr))
+	if err := c.sqlite.error(r, 0); err != nil {
+		c.free(dataPtr)
+		return nil, err
+	}
+
+	iter := ChangesetIterator{c: c, data: dataPtr}
+	iter.handle = util.ReadUint32(c.mod, iterPtr)
+	return &iter, nil
+}
+
+// IterateChangesetStream is like [Conn.IterateChangeset],
+// but reads the changeset or patchset from r.
+//
+// https://sqlite.org/session/sqlite3changeset_start.html
+func (c *Conn) IterateChangesetStream(r io.Reader) (*ChangesetIterator, error) {
+	defer c.arena.mark()()
+	iterPtr := c.arena.new(ptrlen)
+	in := util.AddHandle(c.ctx, strmReader{r})
+
+	rc := c.call("sqlite3changeset_start_strm_go", uint64(iterPtr), uint64(in))
+	if err := c.sqlite.error(rc, 0); err != nil {
+		util.DelHandle(c.ctx, in)
+		return nil, err
+	}
+
+	iter := ChangesetIterator{c: c, strm: in}
+	iter.handle = util.ReadUint32(c.mod, iterPtr)
+	return &iter, nil
+}
+
+// Close finalizes the iterator,
+// returning any error encountered while iterating.
+// It must not be used on iterators passed to a conflict handler.
+//
+// It is safe to close a nil, zero or closed ChangesetIterator.
+//
+// https://sqlite.org/session/sqlite3changeset_finalize.html
+func (iter *ChangesetIterator) Close() error {
+	if iter == nil || iter.handle == 0 {
+		return nil
+	}
+
+	r := iter.c.call("sqlite3changeset_finalize", uint64(iter.handle))
+	iter.c.free(iter.data)
+	util.DelHandle(iter.c.ctx, iter.strm)
+
+	iter.handle = 0
+	iter.data = 0
+	iter.strm = 0
+	if iter.err != nil {
+		return iter.err
+	}
+	return iter.c.sqlite.error(r, 0)
+}
+
+// Next advances the iterator to the next change.
+// It returns false when there are no more changes, or an error occurs.
+// Check [ChangesetIterator.Err] to tell these apart.
+//
+// https://sqlite.org/session/sqlite3changeset_next.html
+func (iter *ChangesetIterator) Next() bool {
+	r := iter.c.call("sqlite3changeset_next", uint64(iter.handle))
+	switch r {
+	case _ROW:
+		return true
+	case _DONE:
+		return false
+	}
+	iter.err = iter.c.sqlite.error(r, 0)
+	return false
+}
+
+// Err gets the last error that occurred while advancing the iterator.
+func (iter *ChangesetIterator) Err() error {
+	return iter.err
+}
+
+// Operation returns information about the current change:
+// the table it affects, the number of columns in that table,
+// the operation ([AUTH_INSERT], [AUTH_DELETE] or [AUTH_UPDATE]),
+// and whether the change is indirect.
+//
+// https://sqlite.org/session/sqlite3changeset_op.html
+func (iter ChangesetIterator) Operation() (table string, numColumns int, op AuthorizerActionCode, indirect bool, err error) {
+	defer iter.c.arena.mark()()
+	tablePtr := iter.c.arena.new(ptrlen)
+	nColPtr := iter.c.arena.new(ptrlen)
+	opPtr := iter.c.arena.new(ptrlen)
+	indirectPtr := iter.c.arena.new(ptrlen)
+
+	r := iter.c.call("sqlite3changeset_op", uint64(iter.handle),
+		uint64(tablePtr), uint64(nColPtr), uint64(opPtr), uint64(indirectPtr))
+	if err = iter.c.sqlite.error(r, 0); err != nil {
+		return
+	}
+
+	table = util.ReadString(iter.c.mod, util.ReadUint32(iter.c.mod, tablePtr), _MAX_NAME)
+	numColumns = int(int32(util.ReadUint32(iter.c.mod, nColPtr)))
+	op = AuthorizerActionCode(util.ReadUint32(iter.c.mod, opPtr))
+	indirect = util.ReadUint32(iter.c.mod, indirectPtr) != 0
+	return
+}
+
+// PrimaryKey reports, for each column of the table affected by the current change,
+// whether that column is part of the table's primary key.
+//
+// https://sqlite.org/session/sqlite3changeset_pk.html
+func (iter ChangesetIterator) PrimaryKey() ([]bool, error) {
+	defer iter.c.arena.mark()()
+	pkPtr := iter.c.arena.new(ptrlen)
+	nColPtr := iter.c.arena.new(ptrlen)
+
+	r := iter.c.call("sqlite3changeset_pk", uint64(iter.handle),
+		uint64(pkPtr), uint64(nColPtr))
+	if err := iter.c.sqlite.error(r, 0); err != nil {
+		return nil, err
+	}
+
+	n := util.ReadUint32(iter.c.mod, nColPtr)
+	ptr := util.ReadUint32(iter.c.mod, pkPtr)
+	pk := make([]bool, n)
+	for i, b := range util.View(iter.c.mod, ptr, uint64(n)) {
+		pk[i] = b != 0
+	}
+	return pk, nil
+}
+
+// Old returns the value of column col of the row before the change.
+// It may only be used with DELETE and UPDATE changes.
+// For UPDATE changes, the zero Value is returned
+// for columns that were not modified.
+// The leftmost column of the table has the index 0.
+//
+// https://sqlite.org/session/sqlite3changeset_old.html
+func (iter ChangesetIterator) Old(col int) (Value, error) {
+	return iter.value("sqlite3changeset_old", col)
+}
+
+// New returns the value of column col of the row after the change.
+// It may only be used with INSERT and UPDATE changes.
+// For UPDATE changes, the zero Value is returned
+// for columns that were not modified.
+// The leftmost column of the table has the index 0.
+//
+// https://sqlite.org/session/sqlite3changeset_new.html
+func (iter ChangesetIterator) New(col int) (Value, error) {
+	return iter.value("sqlite3changeset_new", col)
+}
+
+// Conflict returns the value of column col of the conflicting row.
+// It may only be used from within a conflict handler,
+// with [CHANGESET_DATA] or [CHANGESET_CONFLICT] conflicts.
+// The leftmost column of the table has the index 0.
+//
+// https://sqlite.org/session/sqlite3changeset_conflict.html
+func (iter ChangesetIterator) Conflict(col int) (Value, error) {
+	return iter.value("sqlite3changeset_conflict", col)
+}
+
+func (iter ChangesetIterator) value(call string, col int) (Value, error) {
+	defer iter.c.arena.mark()()
+	valPtr := iter.c.arena.new(ptrlen)
+	r := iter.c.call(call, uint64(iter.handle), uint64(col), uint64(valPtr))
+	if err := iter.c.sqlite.error(r, 0); err != nil {
+		return Value{}, err
+	}
+	handle := util.ReadUint32(iter.c.mod, valPtr)
+	if handle == 0 {
+		return Value{}, nil
+	}
+	return Value{
+		sqlite: iter.c.sqlite,
+		handle: handle,
+	}, nil
+}
+
+// FKConflicts returns the number of foreign key constraint violations.
+// It may only be used from within a conflict handler,
+// with [CHANGESET_FOREIGN_KEY] conflicts.
+//
+// https://sqlite.org/session/sqlite3changeset_fk_conflicts.html
+func (iter ChangesetIterator) FKConflicts() (int, error) {
+	defer iter.c.arena.mark()()
+	nPtr := iter.c.arena.new(ptrlen)
+	r := iter.c.call("sqlite3changeset_fk_conflicts", uint64(iter.handle), uint64(nPtr))
+	if err := iter.c.sqlite.error(r, 0); err != nil {
+		return 0, err
+	}
+	return int(int32(util.ReadUint32(iter.c.mod, nPtr))), nil
+}
+
+func sessionFilterCallback(ctx context.Context, mod api.Module, pApp, zTab uint32) uint32 {
+	h := util.GetHandle(ctx, pApp).(*applyHandlers)
+	if h.filter(util.ReadString(mod, zTab, _MAX_NAME)) {
+		return 1
+	}
+	return 0
+}
+
+func sessionConflictCallback(ctx context.Context, mod api.Module, pApp uint32, eConflict ConflictKind, pIter uint32) ConflictResolution {
+	h := util.GetHandle(ctx, pApp).(*applyHandlers)
+	if h.conflict == nil {
+		return CHANGESET_ABORT
+	}
+	db := ctx.Value(connKey{}).(*Conn)
+	return h.conflict(eConflict, ChangesetIterator{c: db, handle: pIter})
+}
+
+// InvertChangeset returns the inverse of a changeset:
+// inserts become deletes, deletes become inserts,
+// and updates have their old and new values swapped.
+// Patchsets cannot be inverted.
+//
+// https://sqlite.org/session/sqlite3changeset_invert.html
+func (c *Conn) InvertChangeset(changeset []byte) ([]byte, error) {
+	defer c.arena.mark()()
+	nPtr := c.arena.new(ptrlen)
+	outPtr := c.arena.new(ptrlen)
+	inPtr := c.newBytes(changeset)
+	defer c.free(inPtr)
+
+	r := c.call("sqlite3changeset_invert",
+		uint64(len(changeset)), uint64(inPtr), uint64(nPtr), uint64(outPtr))
+	if err := c.sqlite.error(r, 0); err != nil {
+		return nil, err
+	}
+	return c.output(nPtr, outPtr), nil
+}
+
+// InvertChangesetStream is like [Conn.InvertChangeset],
+// but reads the changeset from r and writes its inverse to w.
+//
+// https://sqlite.org/session/sqlite3changeset_invert.html
+func (c *Conn) InvertChangesetStream(w io.Writer, r io.Reader) error {
+	in := util.AddHandle(c.ctx, strmReader{r})
+	defer util.DelHandle(c.ctx, in)
+	out := util.AddHandle(c.ctx, strmWriter{w})
+	defer util.DelHandle(c.ctx, out)
+
+	rc := c.call("sqlite3changeset_invert_strm_go", uint64(in), uint64(out))
+	return c.sqlite.error(rc, 0)
+}
+
+// ConcatChangesets combines several changesets, or several patchsets,
+// into a single one, as if the changes were applied in order.
+//
+// https://sqlite.org/session/changegroup.html
+func (c *Conn) ConcatChangesets(changesets ...[]byte) ([]byte, error) {
+	grp, err := c.newChangegroup()
+	if err != nil {
+		return nil, err
+	}
+	defer c.call("sqlite3changegroup_delete", uint64(grp))
+
+	for _, data := range changesets {
+		ptr := c.newBytes(data)
+		r := c.call("sqlite3changegroup_add", uint64(grp),
+			uint64(len(data)), uint64(ptr))
+		c.free(ptr)
+		if err := c.sqlite.error(r, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	defer c.arena.mark()()
+	nPtr := c.arena.new(ptrlen)
+	outPtr := c.arena.new(ptrlen)
+	r := c.call("sqlite3changegroup_output", uint64(grp), uint64(nPtr), uint64(outPtr))
+	if err := c.sqlite.error(r, 0); err != nil {
+		return nil, err
+	}
+	return c.output(nPtr, outPtr), nil
+}
+
+// ConcatChangesetsStream is like [Conn.ConcatChangesets],
+// but reads the changesets or patchsets from rs and writes the result to w.
+//
+// https://sqlite.org/session/changegroup.html
+func (c *Conn) ConcatChangesetsStream(w io.Writer, rs ...io.Reader) error {
+	grp, err := c.newChangegroup()
+	if err != nil {
+		return err
+	}
+	defer c.call("sqlite3changegroup_delete", uint64(grp))
+
+	for _, r := range rs {
+		in := util.AddHandle(c.ctx, strmReader{r})
+		rc := c.call("sqlite3changegroup_add_strm_go", uint64(grp), uint64(in))
+		util.DelHandle(c.ctx, in)
+		if err := c.sqlite.error(rc, 0); err != nil {
+			return err
+		}
+	}
+
+	out := util.AddHandle(c.ctx, strmWriter{w})
+	defer util.DelHandle(c.ctx, out)
+	rc := c.call("sqlite3changegroup_output_strm_go", uint64(grp), uint64(out))
+	return c.sqlite.error(rc, 0)
+}
+
+func (c *Conn) newChangegroup() (uint32, error) {
+	defer c.arena.mark()()
+	grpPtr := c.arena.new(ptrlen)
+	r := c.call("sqlite3changegroup_new", uint64(grpPtr))
+	if err := c.sqlite.error(r, 0); err != nil {
+		return 0, err
+	}
+	return util.ReadUint32(c.mod, grpPtr), nil
+}
+
+// Rebaser rebases changesets on top of changes
+// applied with [Conn.ApplyChangesetRebase].
+//
+// https://sqlite.org/session/rebaser.html
+type Rebaser struct {
+	c      *Conn
+	handle uint32
+}
+
+// CreateRebaser creates a new rebaser object.
+//
+// https://sqlite.org/session/sqlite3rebaser_create.html
+func (c *Conn) CreateRebaser() (*Rebaser, error) {
+	defer c.arena.mark()()
+	rebaserPtr := c.arena.new(ptrlen)
+
+	r := c.call("sqlite3rebaser_create", uint64(rebaserPtr))
+	if err := c.sqlite.error(r, 0); err != nil {
+		return nil, err
+	}
+
+	rebaser := Rebaser{c: c}
+	rebaser.handle = util.ReadUint32(c.mod, rebaserPtr)
+	return &rebaser, nil
+}
+
+// Close deletes the rebaser object.
+//
+// It is safe to close a nil, zero or closed Rebaser.
+//
+// https://sqlite.org/session/sqlite3rebaser_delete.html
+func (r *Rebaser) Close() error {
+	if r == nil || r.handle == 0 {
+		return nil
+	}
+
+	r.c.call("sqlite3rebaser_delete", uint64(r.handle))
+
+	r.handle = 0
+	return nil
+}
+
+// Configure configures the rebaser with a buffer
+// returned by [Conn.ApplyChangesetRebase].
+// It may be called several times, with several buffers.
+//
+// https://sqlite.org/session/sqlite3rebaser_configure.html
+func (r *Rebaser) Configure(rebase []byte) error {
+	ptr := r.c.newBytes(rebase)
+	defer r.c.free(ptr)
+
+	rc := r.c.call("sqlite3rebaser_configure", uint64(r.handle),
+		uint64(len(rebase)), uint64(ptr))
+	return r.c.sqlite.error(rc, 0)
+}
+
+// Rebase rebases a changeset according to the configuration of the rebaser.
+//
+// https://sqlite.org/session/sqlite3rebaser_rebase.html
+func (r *Rebaser) Rebase(changeset []byte) ([]byte, error) {
+	defer r.c.arena.mark()()
+	nPtr := r.c.arena.new(ptrlen)
+	outPtr := r.c.arena.new(ptrlen)
+	inPtr := r.c.newBytes(changeset)
+	defer r.c.free(inPtr)
+
+	rc := r.c.call("sqlite3rebaser_rebase", uint64(r.handle),
+		uint64(len(changeset)), uint64(inPtr), uint64(nPtr), uint64(outPtr))
+	if err := r.c.sqlite.error(rc, 0); err != nil {
+		return nil, err
+	}
+	return r.c.output(nPtr, outPtr), nil
+}
+
+// RebaseStream is like [Rebaser.Rebase],
+// but reads the changeset from in and writes the result to w.
+//
+// https://sqlite.org/session/sqlite3rebaser_rebase_strm.html
+func (r *Rebaser) RebaseStream(w io.Writer, in io.Reader) error {
+	inh := util.AddHandle(r.c.ctx, strmReader{in})
+	defer util.DelHandle(r.c.ctx, inh)
+	out := util.AddHandle(r.c.ctx, strmWriter{w})
+	defer util.DelHandle(r.c.ctx, out)
+
+	rc := r.c.call("sqlite3rebaser_rebase_strm_go", uint64(r.handle),
+		uint64(inh), uint64(out))
+	return r.c.sqlite.error(rc, 0)
+}
+
+// strmReader and strmWriter hide any Close method
+// from DelHandle: streams are owned by the caller.
+type strmReader struct{ io.Reader }
+type strmWriter struct{ io.Writer }
+
+func strmInputCallback(ctx context.Context, mod api.Module, pIn, pData, pnData uint32) uint32 {
+	r := util.GetHandle(ctx, pIn).(strmReader)
+	buf := util.View(mod, pData, uint64(util.ReadUint32(mod, pnData)))
+	n, err := io.ReadFull(r, buf)
+	util.WriteUint32(mod, pnData, uint32(n))
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		err = nil
+	}
+	_, rc := errorCode(err, IOERR)
+	return rc
+}
+
+func strmOutputCallback(ctx context.Context, mod api.Module, pOut, pData, nData uint32) uint32 {
+	w := util.GetHandle(ctx, pOut).(strmWriter)
+	_, err := w.Write(util.View(mod, pData, uint64(nData)))
+	_, rc := errorCode(err, IOERR)
+	return rc
+}
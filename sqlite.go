@@ -74,7 +74,7 @@ type sqlite struct {
 		id   [32]*byte
 		mask uint32
 	}
-	stack [8]uint64
+	stack [9]uint64
 	freer uint32
 }
 
@@ -282,13 +282,25 @@ func (a *arena) string(s string) uint32 {
 
 func exportCallbacks(env wazero.HostModuleBuilder) wazero.HostModuleBuilder {
 	util.ExportFuncII(env, "go_progress", progressCallback)
+	util.ExportFuncIII(env, "go_busy_handler", busyCallback)
 	util.ExportFuncVI(env, "go_destroy", destroyCallback)
+	util.ExportFuncII(env, "go_commit_hook", commitCallback)
+	util.ExportFuncVI(env, "go_rollback_hook", rollbackCallback)
+	util.ExportFuncVIIIIJ(env, "go_update_hook", updateCallback)
+	util.ExportFuncVIIIIIJJ(env, "go_preupdate_hook", preUpdateCallback)
+	util.ExportFuncIIIIIII(env, "go_authorizer", authorizerCallback)
+	util.ExportFuncIIIII(env, "go_trace", traceCallback)
+	util.ExportFuncIIIII(env, "go_wal_hook", walCallback)
 	util.ExportFuncVIII(env, "go_func", funcCallback)
 	util.ExportFuncVIII(env, "go_step", stepCallback)
 	util.ExportFuncVI(env, "go_final", finalCallback)
 	util.ExportFuncVI(env, "go_value", valueCallback)
 	util.ExportFuncVIII(env, "go_inverse", inverseCallback)
 	util.ExportFuncIIIIII(env, "go_compare", compareCallback)
+	util.ExportFuncIII(env, "go_session_filter", sessionFilterCallback)
+	util.ExportFuncIIII(env, "go_session_conflict", sessionConflictCallback)
+	util.ExportFuncIIII(env, "go_strm_input", strmInputCallback)
+	util.ExportFuncIIII(env, "go_strm_output", strmOutputCallback)
 	util.ExportFuncIIIIII(env, "go_vtab_create", vtabModuleCallback(0))
 	util.ExportFuncIIIIII(env, "go_vtab_connect", vtabModuleCallback(1))
 	util.ExportFuncII(env, "go_vtab_disconnect", vtabDisconnectCallback)
@@ -305,6 +317,7 @@ func exportCallbacks(env wazero.HostModuleBuilder) wazero.HostModuleBuilder {
 	util.ExportFuncIII(env, "go_vtab_release", vtabReleaseCallback)
 	util.ExportFuncIII(env, "go_vtab_rollback_to", vtabRollbackToCallback)
 	util.ExportFuncIIIIII(env, "go_vtab_integrity", vtabIntegrityCallback)
+	util.ExportFuncIII(env, "go_vtab_shadow_name", vtabShadowNameCallback)
 	util.ExportFuncIII(env, "go_cur_open", cursorOpenCallback)
 	util.ExportFuncII(env, "go_cur_close", cursorCloseCallback)
 	util.ExportFuncIIIIII(env, "go_cur_filter", cursorFilterCallback)
@@ -0,0 +1,5 @@
+#include "sqlite3.h"
+
+int sqlite3_db_config_go(sqlite3 *db, int op, int arg, int *pRes) {
+  return sqlite3_db_config(db, op, arg, pRes);
+}
@@ -0,0 +1,46 @@
+#include <stdbool.h>
+
+#include "sqlite3.h"
+
+int go_commit_hook(void *);
+void go_rollback_hook(void *);
+void go_update_hook(void *, int, char const *, char const *, sqlite3_int64);
+int go_authorizer(void *, int, const char *, const char *, const char *,
+                  const char *);
+int go_trace(unsigned, void *, void *, void *);
+int go_busy_handler(void *, int);
+int go_wal_hook(void *, sqlite3 *, const char *, int);
+void go_preupdate_hook(void *, sqlite3 *, int, char const *, char const *,
+                       sqlite3_int64, sqlite3_int64);
+
+void sqlite3_commit_hook_go(sqlite3 *db, bool enable) {
+  sqlite3_commit_hook(db, enable ? go_commit_hook : NULL, db);
+}
+
+void sqlite3_rollback_hook_go(sqlite3 *db, bool enable) {
+  sqlite3_rollback_hook(db, enable ? go_rollback_hook : NULL, db);
+}
+
+void sqlite3_update_hook_go(sqlite3 *db, bool enable) {
+  sqlite3_update_hook(db, enable ? go_update_hook : NULL, db);
+}
+
+int sqlite3_set_authorizer_go(sqlite3 *db, bool enable) {
+  return sqlite3_set_authorizer(db, enable ? go_authorizer : NULL, db);
+}
+
+int sqlite3_trace_go(sqlite3 *db, unsigned mask) {
+  return sqlite3_trace_v2(db, mask, go_trace, db);
+}
+
+int sqlite3_busy_handler_go(sqlite3 *db, bool enable) {
+  return sqlite3_busy_handler(db, enable ? go_busy_handler : NULL, db);
+}
+
+void sqlite3_wal_hook_go(sqlite3 *db, bool enable) {
+  sqlite3_wal_hook(db, enable ? go_wal_hook : NULL, db);
+}
+
+void sqlite3_preupdate_hook_go(sqlite3 *db, bool enable) {
+  sqlite3_preupdate_hook(db, enable ? go_preupdate_hook : NULL, db);
+}
@@ -9,9 +9,12 @@
 #include "ext/uint.c"
 #include "ext/uuid.c"
 // Bindings
+#include "config.c"
 #include "func.c"
+#include "hooks.c"
 #include "pointer.c"
 #include "progress.c"
+#include "session.c"
 #include "time.c"
 #include "vfs.c"
 #include "vtab.c"
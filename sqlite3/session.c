@@ -0,0 +1,60 @@
+#include <stdbool.h>
+
+#include "include.h"
+#include "sqlite3.h"
+
+int go_session_filter(go_handle, const char *zTab);
+int go_session_conflict(go_handle, int eConflict, sqlite3_changeset_iter *);
+
+int go_strm_input(go_handle, void *pData, int *pnData);
+int go_strm_output(go_handle, const void *pData, int nData);
+
+int sqlite3changeset_apply_go(sqlite3 *db, int nChangeset, void *pChangeset,
+                              int *pnRebase, void **ppRebase, bool filter,
+                              go_handle handle) {
+  return sqlite3changeset_apply_v2(db, nChangeset, pChangeset,
+                                   filter ? go_session_filter : NULL,
+                                   go_session_conflict, handle, ppRebase,
+                                   pnRebase, /*flags=*/0);
+}
+
+int sqlite3changeset_apply_strm_go(sqlite3 *db, go_handle in, bool filter,
+                                   go_handle handle) {
+  return sqlite3changeset_apply_strm(db, go_strm_input, in,
+                                     filter ? go_session_filter : NULL,
+                                     go_session_conflict, handle);
+}
+
+int sqlite3session_changeset_strm_go(sqlite3_session *pSession,
+                                     go_handle out) {
+  return sqlite3session_changeset_strm(pSession, go_strm_output, out);
+}
+
+int sqlite3session_patchset_strm_go(sqlite3_session *pSession,
+                                    go_handle out) {
+  return sqlite3session_patchset_strm(pSession, go_strm_output, out);
+}
+
+int sqlite3changeset_start_strm_go(sqlite3_changeset_iter **pp,
+                                   go_handle in) {
+  return sqlite3changeset_start_strm(pp, go_strm_input, in);
+}
+
+int sqlite3changeset_invert_strm_go(go_handle in, go_handle out) {
+  return sqlite3changeset_invert_strm(go_strm_input, in, go_strm_output, out);
+}
+
+int sqlite3changegroup_add_strm_go(sqlite3_changegroup *pGrp, go_handle in) {
+  return sqlite3changegroup_add_strm(pGrp, go_strm_input, in);
+}
+
+int sqlite3changegroup_output_strm_go(sqlite3_changegroup *pGrp,
+                                      go_handle out) {
+  return sqlite3changegroup_output_strm(pGrp, go_strm_output, out);
+}
+
+int sqlite3rebaser_rebase_strm_go(sqlite3_rebaser *pRebaser, go_handle in,
+                                  go_handle out) {
+  return sqlite3rebaser_rebase_strm(pRebaser, go_strm_input, in,
+                                    go_strm_output, out);
+}
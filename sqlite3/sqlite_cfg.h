@@ -33,7 +33,9 @@
 
 #define SQLITE_DQS 0
 #define SQLITE_THREADSAFE 0
-#define SQLITE_DEFAULT_MEMSTATUS 0
+#ifndef SQLITE_DEFAULT_MEMSTATUS
+#define SQLITE_DEFAULT_MEMSTATUS 1
+#endif
 #define SQLITE_DEFAULT_WAL_SYNCHRONOUS 1
 #define SQLITE_LIKE_DOESNT_MATCH_BLOBS
 #define SQLITE_MAX_EXPR_DEPTH 0
@@ -51,6 +53,9 @@
 #define SQLITE_DEFAULT_FOREIGN_KEYS 1
 #define SQLITE_ENABLE_ATOMIC_WRITE
 #define SQLITE_ENABLE_BATCH_ATOMIC_WRITE
+#define SQLITE_ENABLE_COLUMN_METADATA
+#define SQLITE_ENABLE_NORMALIZE
+#define SQLITE_ENABLE_STMT_SCANSTATUS
 
 // Because WASM does not support shared memory,
 // SQLite disables WAL for WASM builds.
@@ -58,12 +63,6 @@
 // https://sqlite.org/wal.html#noshm
 #undef SQLITE_OMIT_WAL
 
-// We have our own memdb VFS.
-// To avoid interactions between the two,
-// omit sqlite3_serialize/sqlite3_deserialize,
-// which we also don't wrap.
-#define SQLITE_OMIT_DESERIALIZE
-
 // Amalgamated Extensions
 
 #define SQLITE_ENABLE_MATH_FUNCTIONS 1
@@ -78,8 +77,8 @@
 #define SQLITE_SOUNDEX
 
 // Session Extension
-// #define SQLITE_ENABLE_SESSION
-// #define SQLITE_ENABLE_PREUPDATE_HOOK
+#define SQLITE_ENABLE_SESSION
+#define SQLITE_ENABLE_PREUPDATE_HOOK
 
 // Implemented in vfs.c.
 int localtime_s(struct tm *const pTm, time_t const *const pTime);
\ No newline at end of file
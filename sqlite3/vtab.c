@@ -11,6 +11,7 @@
 #define SQLITE_VTAB_CHECKER_GO /******/ 0x20
 #define SQLITE_VTAB_TX_GO /***********/ 0x40
 #define SQLITE_VTAB_SAVEPOINTER_GO /**/ 0x80
+#define SQLITE_VTAB_SHADOWTABS_GO /***/ 0x100
 
 int go_vtab_create(sqlite3_module *, int argc, const char *const *argv,
                    sqlite3_vtab **, char **pzErr);
@@ -47,6 +48,8 @@ int go_vtab_rollback_to(sqlite3_vtab *, int);
 int go_vtab_integrity(sqlite3_vtab *, const char *zSchema, const char *zTabName,
                       int mFlags, char **pzErr);
 
+int go_vtab_shadow_name(sqlite3_module *, const char *zName);
+
 struct go_module {
   go_handle handle;
   sqlite3_module base;
@@ -62,8 +65,48 @@ struct go_cursor {
   sqlite3_vtab_cursor base;
 };
 
+// xShadowName gets no module pointer, so each module that implements it
+// takes one of a fixed number of trampolines, which passes it along.
+#define GO_SHADOW_NAME_SLOTS 16
+
+static sqlite3_module *go_shadow_name_mods[GO_SHADOW_NAME_SLOTS];
+
+#define GO_SHADOW_NAME(i)                                      \
+  static int go_vtab_shadow_name_##i(const char *zName) {      \
+    return go_vtab_shadow_name(go_shadow_name_mods[i], zName); \
+  }
+
+GO_SHADOW_NAME(0)
+GO_SHADOW_NAME(1)
+GO_SHADOW_NAME(2)
+GO_SHADOW_NAME(3)
+GO_SHADOW_NAME(4)
+GO_SHADOW_NAME(5)
+GO_SHADOW_NAME(6)
+GO_SHADOW_NAME(7)
+GO_SHADOW_NAME(8)
+GO_SHADOW_NAME(9)
+GO_SHADOW_NAME(10)
+GO_SHADOW_NAME(11)
+GO_SHADOW_NAME(12)
+GO_SHADOW_NAME(13)
+GO_SHADOW_NAME(14)
+GO_SHADOW_NAME(15)
+
+static int (*const go_shadow_name_funcs[])(const char *) = {
+    go_vtab_shadow_name_0,  go_vtab_shadow_name_1,  go_vtab_shadow_name_2,
+    go_vtab_shadow_name_3,  go_vtab_shadow_name_4,  go_vtab_shadow_name_5,
+    go_vtab_shadow_name_6,  go_vtab_shadow_name_7,  go_vtab_shadow_name_8,
+    go_vtab_shadow_name_9,  go_vtab_shadow_name_10, go_vtab_shadow_name_11,
+    go_vtab_shadow_name_12, go_vtab_shadow_name_13, go_vtab_shadow_name_14,
+    go_vtab_shadow_name_15,
+};
+
 static void go_mod_destroy(void *pAux) {
   struct go_module *mod = pAux;
+  for (int i = 0; i < GO_SHADOW_NAME_SLOTS; i++) {
+    if (go_shadow_name_mods[i] == &mod->base) go_shadow_name_mods[i] = NULL;
+  }
   void *handle = mod->handle;
   free(mod);
   go_destroy(handle);
@@ -206,6 +249,20 @@ int sqlite3_create_module_go(sqlite3 *db, const char *zName, int flags,
     mod->base.xRelease = go_vtab_release;
     mod->base.xRollbackTo = go_vtab_rollback_to;
   }
+  if (flags & SQLITE_VTAB_SHADOWTABS_GO) {
+    for (int i = 0; i < GO_SHADOW_NAME_SLOTS; i++) {
+      if (go_shadow_name_mods[i] == NULL) {
+        go_shadow_name_mods[i] = &mod->base;
+        mod->base.xShadowName = go_shadow_name_funcs[i];
+        break;
+      }
+    }
+    if (mod->base.xShadowName == NULL) {
+      free(mod);
+      go_destroy(handle);
+      return SQLITE_NOMEM;
+    }
+  }
   if (mod->base.xCreate && !mod->base.xDestroy) {
     mod->base.xDestroy = mod->base.xDisconnect;
   }
@@ -1,8 +1,10 @@
 package sqlite3
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"math"
+	"reflect"
 	"strconv"
 	"time"
 
@@ -15,7 +17,9 @@ import (
 type Stmt struct {
 	c      *Conn
 	err    error
+	sql    string
 	handle uint32
+	cached bool
 }
 
 // Close destroys the prepared statement object.
@@ -28,6 +32,23 @@ func (s *Stmt) Close() error {
 		return nil
 	}
 
+	if s.cached && s.c.cache.size > 0 {
+		// Reset reports the error of the last Step, like finalize would,
+		// but the statement is reset regardless, and can be reused.
+		err := s.Reset()
+		s.ClearBindings()
+
+		// Cache a new wrapper, so that this one stays closed,
+		// even after the statement is handed out again.
+		idle := &Stmt{c: s.c, sql: s.sql, handle: s.handle, cached: true}
+		s.c.stmts[s.handle] = idle
+		s.c.cache.put(idle)
+		s.handle = 0
+		return err
+	}
+
+	delete(s.c.stmts, s.handle)
+
 	r := s.c.call("sqlite3_finalize", uint64(s.handle))
 
 	s.handle = 0
@@ -56,7 +77,7 @@ func (s *Stmt) ReadOnly() bool {
 func (s *Stmt) Reset() error {
 	r := s.c.call("sqlite3_reset", uint64(s.handle))
 	s.err = nil
-	return s.c.error(r)
+	return s.error(r)
 }
 
 // Busy determines if a prepared statement has been reset.
@@ -86,7 +107,7 @@ func (s *Stmt) Step() bool {
 	case _DONE:
 		s.err = nil
 	default:
-		s.err = s.c.error(r)
+		s.err = s.error(r)
 	}
 	return false
 }
@@ -348,6 +369,44 @@ func (s *Stmt) BindValue(param int, value Value) error {
 	return s.c.error(r)
 }
 
+// SQL returns the SQL text used to create the prepared statement.
+//
+// https://sqlite.org/c3ref/expanded_sql.html
+func (s *Stmt) SQL() string {
+	r := s.c.call("sqlite3_sql", uint64(s.handle))
+	if r == 0 {
+		return ""
+	}
+	return util.ReadString(s.c.mod, uint32(r), _MAX_SQL_LENGTH)
+}
+
+// ExpandedSQL returns the SQL text of the prepared statement
+// with bound parameters expanded.
+//
+// https://sqlite.org/c3ref/expanded_sql.html
+func (s *Stmt) ExpandedSQL() string {
+	r := s.c.call("sqlite3_expanded_sql", uint64(s.handle))
+	if r == 0 {
+		return ""
+	}
+	sql := util.ReadString(s.c.mod, uint32(r), _MAX_SQL_LENGTH)
+	s.c.call("sqlite3_free", r)
+	return sql
+}
+
+// NormalizedSQL returns the normalized SQL text of the prepared statement:
+// literals are replaced by "?", and whitespace and keyword case are normalized.
+// Statements that differ only in their literals have the same normalized SQL.
+//
+// https://sqlite.org/c3ref/expanded_sql.html
+func (s *Stmt) NormalizedSQL() string {
+	r := s.c.call("sqlite3_normalized_sql", uint64(s.handle))
+	if r == 0 {
+		return ""
+	}
+	return util.ReadString(s.c.mod, uint32(r), _MAX_SQL_LENGTH)
+}
+
 // ColumnCount returns the number of columns in a result set.
 //
 // https://sqlite.org/c3ref/column_count.html
@@ -395,6 +454,44 @@ func (s *Stmt) ColumnDeclType(col int) string {
 	return util.ReadString(s.c.mod, uint32(r), _MAX_NAME)
 }
 
+// ColumnDatabaseName returns the name of the database
+// that is the origin of a particular result column.
+// It returns an empty string if the result column is an expression or subquery.
+// The leftmost column of the result set has the index 0.
+//
+// https://sqlite.org/c3ref/column_database_name.html
+func (s *Stmt) ColumnDatabaseName(col int) string {
+	return s.columnName("sqlite3_column_database_name", col)
+}
+
+// ColumnTableName returns the name of the table
+// that is the origin of a particular result column.
+// It returns an empty string if the result column is an expression or subquery.
+// The leftmost column of the result set has the index 0.
+//
+// https://sqlite.org/c3ref/column_database_name.html
+func (s *Stmt) ColumnTableName(col int) string {
+	return s.columnName("sqlite3_column_table_name", col)
+}
+
+// ColumnOriginName returns the name of the table column
+// that is the origin of a particular result column.
+// It returns an empty string if the result column is an expression or subquery.
+// The leftmost column of the result set has the index 0.
+//
+// https://sqlite.org/c3ref/column_database_name.html
+func (s *Stmt) ColumnOriginName(col int) string {
+	return s.columnName("sqlite3_column_origin_name", col)
+}
+
+func (s *Stmt) columnName(call string, col int) string {
+	r := s.c.call(call, uint64(s.handle), uint64(col))
+	if r == 0 {
+		return ""
+	}
+	return util.ReadString(s.c.mod, uint32(r), _MAX_NAME)
+}
+
 // ColumnBool returns the value of the result column as a bool.
 // The leftmost column of the result set has the index 0.
 // SQLite does not have a separate boolean storage class.
@@ -552,3 +649,295 @@ func (s *Stmt) ColumnValue(col int) Value {
 		handle: uint32(r),
 	}
 }
+
+// Bind binds args to the parameters of the prepared statement,
+// in order: the first argument binds to the parameter with index 1.
+// Arguments are bound according to their Go type,
+// as described in [Stmt.BindAny].
+func (s *Stmt) Bind(args ...any) error {
+	for i, arg := range args {
+		if err := s.BindAny(i+1, arg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BindNamed binds args to the named parameters of the prepared statement.
+// Names may include their prefix (":", "@" or "$");
+// names without a prefix bind to parameters with any of these prefixes.
+// Names that match no parameter are ignored.
+// Arguments are bound according to their Go type,
+// as described in [Stmt.BindAny].
+func (s *Stmt) BindNamed(args map[string]any) error {
+	for name, arg := range args {
+		if err := s.bindNamed(name, arg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Stmt) bindNamed(name string, arg any) error {
+	if name == "" {
+		return nil
+	}
+	switch name[0] {
+	case ':', '@', '$', '?':
+		if id := s.BindIndex(name); id != 0 {
+			return s.BindAny(id, arg)
+		}
+		return nil
+	}
+	for _, prefix := range [...]string{":", "@", "$"} {
+		if id := s.BindIndex(prefix + name); id != 0 {
+			if err := s.BindAny(id, arg); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// BindAny binds a value of any supported Go type to the prepared statement.
+// The leftmost SQL parameter has an index of 1.
+//
+// Supported types are:
+// nil, bool, integer and floating-point types, string, []byte,
+// [ZeroBlob], [time.Time] (bound with [TimeFormatDefault]), [Value],
+// values returned by [Pointer] and [JSON],
+// [driver.Valuer] implementations, types with one of these as underlying type,
+// and pointers to any of these (nil pointers bind NULL).
+func (s *Stmt) BindAny(param int, arg any) error {
+	arg, err := normalizeAny(arg)
+	if err != nil {
+		return err
+	}
+	switch a := arg.(type) {
+	case nil:
+		return s.BindNull(param)
+	case bool:
+		return s.BindBool(param, a)
+	case int64:
+		return s.BindInt64(param, a)
+	case float64:
+		return s.BindFloat(param, a)
+	case string:
+		return s.BindText(param, a)
+	case []byte:
+		return s.BindBlob(param, a)
+	case ZeroBlob:
+		return s.BindZeroBlob(param, int64(a))
+	case time.Time:
+		return s.BindTime(param, a, TimeFormatDefault)
+	case Value:
+		return s.BindValue(param, a)
+	case interface{ Pointer() any }:
+		return s.BindPointer(param, a.Pointer())
+	case interface{ JSON() any }:
+		return s.BindJSON(param, a.JSON())
+	}
+	return util.ValueErr
+}
+
+// normalizeAny converts a Go value to one of: nil, bool, int64, float64,
+// string, []byte, [ZeroBlob], [time.Time], [Value],
+// or a value returned by [Pointer] or [JSON];
+// calling [driver.Valuer] implementations, dereferencing pointers,
+// and converting types with a supported underlying type.
+func normalizeAny(arg any) (any, error) {
+	switch a := arg.(type) {
+	case nil, bool, int64, float64, string, []byte, ZeroBlob, time.Time, Value:
+		return arg, nil
+	case int:
+		return int64(a), nil
+	case interface{ Pointer() any }, interface{ JSON() any }:
+		return arg, nil
+	case driver.Valuer:
+		v, err := callValuer(a)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := v.(driver.Valuer); ok {
+			return nil, util.ValueErr
+		}
+		return normalizeAny(v)
+	}
+
+	v := reflect.ValueOf(arg)
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return normalizeAny(v.Elem().Interface())
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := v.Uint()
+		if u > math.MaxInt64 {
+			return nil, util.ValueErr
+		}
+		return int64(u), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Bytes(), nil
+		}
+	}
+	return nil, util.ValueErr
+}
+
+func callValuer(v driver.Valuer) (driver.Value, error) {
+	// Nil pointers to value receivers bind NULL, like database/sql.
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() &&
+		rv.Type().Elem().Implements(reflect.TypeOf((*driver.Valuer)(nil)).Elem()) {
+		return nil, nil
+	}
+	return v.Value()
+}
+
+// Scan copies the columns of the current row into the values pointed to by dest.
+// The number of values in dest must match the number of columns.
+//
+// Supported destinations are:
+// pointers to bool, integer and floating-point types, string, []byte,
+// [time.Time] (decoded with [TimeFormatAuto]) and any,
+// pointers to types with one of these as underlying type,
+// and implementations of [database/sql.Scanner],
+// which include the values returned by [JSON] and [TimeFormat.Scanner].
+// A pointer to a pointer is set to nil if the column is NULL;
+// otherwise, a new value is allocated and scanned into.
+// NULL is scanned into other types as the zero value.
+func (s *Stmt) Scan(dest ...any) error {
+	if len(dest) != s.ColumnCount() {
+		return util.RangeErr
+	}
+	for i, d := range dest {
+		if err := s.scan(i, d); err != nil {
+			return err
+		}
+		if s.err != nil {
+			return s.err
+		}
+	}
+	return nil
+}
+
+func (s *Stmt) scan(col int, dest any) error {
+	switch d := dest.(type) {
+	case interface{ Scan(any) error }:
+		return d.Scan(s.columnAny(col))
+	case *any:
+		*d = s.columnAny(col)
+		return nil
+	case *string:
+		*d = s.ColumnText(col)
+		return nil
+	case *[]byte:
+		if s.ColumnType(col) == NULL {
+			*d = nil
+		} else {
+			// Copy into a new slice, like database/sql:
+			// callers may keep the slices of previous rows.
+			*d = s.ColumnBlob(col, []byte{})
+		}
+		return nil
+	case *int:
+		*d = s.ColumnInt(col)
+		return nil
+	case *int64:
+		*d = s.ColumnInt64(col)
+		return nil
+	case *float64:
+		*d = s.ColumnFloat(col)
+		return nil
+	case *bool:
+		*d = s.ColumnBool(col)
+		return nil
+	case *time.Time:
+		*d = s.ColumnTime(col, TimeFormatAuto)
+		return nil
+	}
+
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return util.ValueErr
+	}
+	v = v.Elem()
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if s.ColumnType(col) == NULL {
+			v.SetZero()
+			return nil
+		}
+		p := reflect.New(v.Type().Elem())
+		if err := s.scan(col, p.Interface()); err != nil {
+			return err
+		}
+		v.Set(p)
+	case reflect.Bool:
+		v.SetBool(s.ColumnBool(col))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := s.ColumnInt64(col)
+		if v.OverflowInt(i) {
+			return util.ValueErr
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		i := s.ColumnInt64(col)
+		if i < 0 || v.OverflowUint(uint64(i)) {
+			return util.ValueErr
+		}
+		v.SetUint(uint64(i))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(s.ColumnFloat(col))
+	case reflect.String:
+		v.SetString(s.ColumnText(col))
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return util.ValueErr
+		}
+		if s.ColumnType(col) == NULL {
+			v.SetZero()
+		} else {
+			v.SetBytes(s.ColumnBlob(col, []byte{}))
+		}
+	default:
+		return util.ValueErr
+	}
+	return nil
+}
+
+func (s *Stmt) columnAny(col int) any {
+	switch s.ColumnType(col) {
+	case INTEGER:
+		return s.ColumnInt64(col)
+	case FLOAT:
+		return s.ColumnFloat(col)
+	case TEXT:
+		return s.ColumnText(col)
+	case BLOB:
+		return s.ColumnBlob(col, nil)
+	case NULL:
+		return nil
+	default:
+		panic(util.AssertErr())
+	}
+}
+
+func (s *Stmt) error(rc uint64) error {
+	err := s.c.error(rc)
+	// Reporting an error must never fail:
+	// skip binaries that don't export sqlite3_expanded_sql.
+	if err, ok := err.(*Error); ok && s.c.mod.ExportedFunction("sqlite3_expanded_sql") != nil {
+		err.stmt = s.ExpandedSQL()
+	}
+	return err
+}
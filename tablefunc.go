@@ -0,0 +1,233 @@
+package sqlite3
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ncruces/go-sqlite3/internal/util"
+)
+
+// CreateTableFunction registers a new table-valued SQL function.
+//
+// Each of columns and params is a column definition,
+// like "value" or "value INTEGER".
+// The params are hidden columns, and all of them are required:
+// they're passed to fn, either as function arguments, as in
+// SELECT * FROM name(1, 2), or as equality constraints, as in
+// SELECT * FROM name WHERE param1 = 1 AND param2 = 2.
+//
+// fn is called once for each query, with the params in order,
+// and should call yield for each row it generates,
+// stopping if yield returns false.
+// Row values are returned as described in [Stmt.BindAny];
+// missing values are NULL.
+// Rows are generated lazily: fn runs in its own goroutine,
+// but never concurrently with the connection,
+// as yield blocks until SQLite asks for the next row;
+// yield returns false once the query stops reading rows.
+// Values passed to yield need only be valid until yield returns.
+//
+// https://sqlite.org/vtab.html#table_valued_functions
+func CreateTableFunction(db *Conn, name string, columns, params []string, fn func(args []Value, yield func(row ...any) bool) error) error {
+	var decl strings.Builder
+	decl.WriteString("CREATE TABLE x(")
+	for i, col := range columns {
+		if i > 0 {
+			decl.WriteString(",")
+		}
+		decl.WriteString(col)
+	}
+	for i, param := range params {
+		if i > 0 || len(columns) > 0 {
+			decl.WriteString(",")
+		}
+		decl.WriteString(param)
+		decl.WriteString(" HIDDEN")
+	}
+	decl.WriteString(")")
+
+	return CreateEponymousOnlyModule(db, name,
+		func(db *Conn, _, _, _ string, _ ...string) (tableFunc, error) {
+			err := db.DeclareVtab(decl.String())
+			return tableFunc{fn, len(columns), len(params)}, err
+		})
+}
+
+type tableFunc struct {
+	fn      func(args []Value, yield func(row ...any) bool) error
+	columns int
+	params  int
+}
+
+func (t tableFunc) BestIndex(idx *IndexInfo) error {
+	found := 0
+	for i, cst := range idx.Constraint {
+		param := cst.Column - t.columns
+		if param < 0 || param >= t.params ||
+			cst.Op != INDEX_CONSTRAINT_EQ || !cst.Usable ||
+			found&(1<<param) != 0 {
+			continue
+		}
+		found |= 1 << param
+		idx.ConstraintUsage[i] = IndexConstraintUsage{
+			ArgvIndex: param + 1,
+			Omit:      true,
+		}
+	}
+	if found != 1<<t.params-1 {
+		return CONSTRAINT
+	}
+	idx.EstimatedCost = 1
+	idx.EstimatedRows = 100
+	return nil
+}
+
+func (t tableFunc) Open() (VTabCursor, error) {
+	return &tableFuncCursor{tableFunc: t}, nil
+}
+
+// A tableFuncCursor runs fn in a goroutine,
+// handing control back and forth with the cursor,
+// so that only one of them ever runs at a time.
+type tableFuncCursor struct {
+	tableFunc
+	args   []*Value
+	row    []any
+	rowID  int64
+	resume chan bool  // to fn: true to generate the next row, false to stop
+	rows   chan []any // from fn: the next row; closed when fn returns
+	err    error      // set by fn before rows is closed
+	panic  any        // set by fn before rows is closed
+}
+
+func (c *tableFuncCursor) Filter(idxNum int, idxStr string, arg ...Value) error {
+	c.stop()
+	for _, a := range arg {
+		c.args = append(c.args, a.Dup())
+	}
+	args := make([]Value, len(c.args))
+	for i, a := range c.args {
+		args[i] = *a
+	}
+
+	resume := make(chan bool)
+	rows := make(chan []any)
+	c.resume, c.rows, c.rowID = resume, rows, -1
+	go func() {
+		defer close(rows)
+		defer func() { c.panic = recover() }()
+		if !<-resume {
+			return
+		}
+		stopped := false
+		c.err = c.fn(args, func(row ...any) bool {
+			if stopped {
+				return false
+			}
+			rows <- row
+			stopped = !<-resume
+			return !stopped
+		})
+	}()
+	return c.Next()
+}
+
+func (c *tableFuncCursor) Next() error {
+	c.resume <- true
+	return c.recv()
+}
+
+// recv waits for fn to generate a row, or return.
+func (c *tableFuncCursor) recv() error {
+	row, ok := <-c.rows
+	if ok {
+		c.row = row
+		c.rowID++
+		return nil
+	}
+	c.row, c.rows = nil, nil
+	if p := c.panic; p != nil {
+		c.panic = nil
+		panic(p)
+	}
+	err := c.err
+	c.err = nil
+	return err
+}
+
+// stop makes yield return false, waits for fn to return,
+// and frees the arguments of the previous Filter.
+func (c *tableFuncCursor) stop() {
+	var p any
+	if c.rows != nil {
+		c.resume <- false
+		<-c.rows // wait for fn to return
+		p = c.panic
+		c.row, c.rows, c.err, c.panic = nil, nil, nil, nil
+	}
+	for _, a := range c.args {
+		a.Close()
+	}
+	c.args = c.args[:0]
+	if p != nil {
+		panic(p)
+	}
+}
+
+func (c *tableFuncCursor) Close() error {
+	c.stop()
+	return nil
+}
+
+func (c *tableFuncCursor) Column(ctx *Context, n int) error {
+	if n >= c.columns {
+		ctx.ResultValue(*c.args[n-c.columns])
+		return nil
+	}
+	if n < len(c.row) {
+		return ctx.resultAny(c.row[n])
+	}
+	return nil
+}
+
+func (c *tableFuncCursor) EOF() bool {
+	return c.rows == nil
+}
+
+func (c *tableFuncCursor) RowID() (int64, error) {
+	return c.rowID, nil
+}
+
+func (ctx Context) resultAny(arg any) error {
+	arg, err := normalizeAny(arg)
+	if err != nil {
+		return err
+	}
+	switch a := arg.(type) {
+	case nil:
+		ctx.ResultNull()
+	case bool:
+		ctx.ResultBool(a)
+	case int64:
+		ctx.ResultInt64(a)
+	case float64:
+		ctx.ResultFloat(a)
+	case string:
+		ctx.ResultText(a)
+	case []byte:
+		ctx.ResultBlob(a)
+	case ZeroBlob:
+		ctx.ResultZeroBlob(int64(a))
+	case time.Time:
+		ctx.ResultTime(a, TimeFormatDefault)
+	case Value:
+		ctx.ResultValue(a)
+	case interface{ Pointer() any }:
+		ctx.ResultPointer(a.Pointer())
+	case interface{ JSON() any }:
+		ctx.ResultJSON(a.JSON())
+	default:
+		return util.ValueErr
+	}
+	return nil
+}
@@ -3,11 +3,13 @@ package tests
 import (
 	"context"
 	"errors"
+	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ncruces/go-sqlite3"
 	_ "github.com/ncruces/go-sqlite3/embed"
@@ -289,3 +291,1089 @@ func TestConn_Prepare_invalid(t *testing.T) {
 		t.Error("got message:", got)
 	}
 }
+
+func TestConn_UpdateHook(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`CREATE TABLE test (col)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type update struct {
+		action sqlite3.AuthorizerActionCode
+		schema string
+		table  string
+		rowid  int64
+	}
+
+	var got []update
+	db.UpdateHook(func(action sqlite3.AuthorizerActionCode, schema, table string, rowid int64) {
+		got = append(got, update{action, schema, table, rowid})
+	})
+
+	err = db.Exec(`
+		INSERT INTO test VALUES (1), (2);
+		UPDATE test SET col = 3 WHERE rowid = 2;
+		DELETE FROM test WHERE rowid = 1;
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []update{
+		{sqlite3.AUTH_INSERT, "main", "test", 1},
+		{sqlite3.AUTH_INSERT, "main", "test", 2},
+		{sqlite3.AUTH_UPDATE, "main", "test", 2},
+		{sqlite3.AUTH_DELETE, "main", "test", 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	db.UpdateHook(nil)
+
+	got = nil
+	err = db.Exec(`INSERT INTO test VALUES (4)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+}
+
+func TestConn_CommitHook(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`CREATE TABLE test (col)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var commits, rollbacks int
+	db.RollbackHook(func() { rollbacks++ })
+	db.CommitHook(func() bool {
+		commits++
+		return commits < 3
+	})
+
+	// Implicit (autocommit) transaction.
+	err = db.Exec(`INSERT INTO test VALUES (1)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Explicit transaction.
+	err = db.Exec(`BEGIN; INSERT INTO test VALUES (2); COMMIT;`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Commit converted into a rollback.
+	err = db.Exec(`INSERT INTO test VALUES (3)`)
+	if !errors.Is(err, sqlite3.CONSTRAINT_COMMITHOOK) {
+		t.Errorf("got %v, want sqlite3.CONSTRAINT_COMMITHOOK", err)
+	}
+
+	// Explicit rollback.
+	err = db.Exec(`BEGIN; INSERT INTO test VALUES (4); ROLLBACK;`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if commits != 3 {
+		t.Errorf("got %d commits, want 3", commits)
+	}
+	if rollbacks != 2 {
+		t.Errorf("got %d rollbacks, want 2", rollbacks)
+	}
+
+	stmt, _, err := db.Prepare(`SELECT count(*) FROM test`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+	if got := stmt.ColumnInt(0); got != 2 {
+		t.Errorf("got %d rows, want 2", got)
+	}
+
+	db.CommitHook(nil)
+	db.RollbackHook(nil)
+
+	err = db.Exec(`BEGIN; INSERT INTO test VALUES (5); ROLLBACK;`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commits != 3 || rollbacks != 2 {
+		t.Errorf("got %d commits, %d rollbacks after unregistering", commits, rollbacks)
+	}
+}
+
+func TestConn_SetAuthorizer(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`
+		CREATE TABLE public (col);
+		CREATE TABLE secret (col);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.SetAuthorizer(func(action sqlite3.AuthorizerActionCode, name3, name4, schema, trigger string) sqlite3.AuthorizerReturnCode {
+		switch action {
+		case sqlite3.AUTH_ATTACH:
+			return sqlite3.AUTH_DENY
+		case sqlite3.AUTH_PRAGMA:
+			if name4 != "" {
+				return sqlite3.AUTH_DENY
+			}
+		case sqlite3.AUTH_READ:
+			if name3 == "secret" {
+				return sqlite3.AUTH_DENY
+			}
+		}
+		return sqlite3.AUTH_OK
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, _, err := db.Prepare(`SELECT * FROM public`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stmt.Close()
+
+	stmt, _, err = db.Prepare(`PRAGMA foreign_keys`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stmt.Close()
+
+	for _, sql := range []string{
+		`SELECT * FROM secret`,
+		`PRAGMA foreign_keys = off`,
+		`ATTACH ':memory:' AS other`,
+	} {
+		_, _, err = db.Prepare(sql)
+		if !errors.Is(err, sqlite3.AUTH) {
+			t.Errorf("%s: got %v, want sqlite3.AUTH", sql, err)
+		}
+	}
+
+	err = db.SetAuthorizer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, _, err = db.Prepare(`SELECT * FROM secret`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stmt.Close()
+}
+
+func TestConn_Trace(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Exec(`
+		CREATE TABLE test (col);
+		CREATE TABLE audit (col);
+		CREATE TRIGGER log AFTER INSERT ON test BEGIN
+			INSERT INTO audit VALUES (new.col);
+		END;
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rows, profiles, closes int
+	var sqls []string
+	var stmts []*sqlite3.Stmt
+	err = db.Trace(sqlite3.TRACE_STMT|sqlite3.TRACE_PROFILE|sqlite3.TRACE_ROW|sqlite3.TRACE_CLOSE,
+		func(evt sqlite3.TraceEvent, arg1, arg2 any) error {
+			switch evt {
+			case sqlite3.TRACE_STMT:
+				stmts = append(stmts, arg1.(*sqlite3.Stmt))
+				sqls = append(sqls, arg2.(string))
+			case sqlite3.TRACE_PROFILE:
+				if arg2.(time.Duration) < 0 {
+					t.Error("negative duration")
+				}
+				profiles++
+			case sqlite3.TRACE_ROW:
+				rows++
+			case sqlite3.TRACE_CLOSE:
+				if arg1 != db {
+					t.Errorf("got %v, want %v", arg1, db)
+				}
+				closes++
+			}
+			return nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, _, err := db.Prepare(`INSERT INTO test VALUES (?) RETURNING col`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = stmt.BindInt(1, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = stmt.Exec()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		`INSERT INTO test VALUES (42) RETURNING col`,
+		`-- TRIGGER log`,
+	}
+	if !reflect.DeepEqual(sqls, want) {
+		t.Errorf("got %q, want %q", sqls, want)
+	}
+	if len(stmts) != 2 || stmts[0] != stmt || stmts[1] != stmt {
+		t.Errorf("got %v, want %v", stmts, stmt)
+	}
+	if rows != 1 {
+		t.Errorf("got %d rows, want 1", rows)
+	}
+	if profiles != 1 {
+		t.Errorf("got %d profiles, want 1", profiles)
+	}
+
+	err = stmt.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Statements the Go side did not prepare.
+	sqls, stmts = nil, nil
+	err = db.Exec(`DELETE FROM audit`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stmts) != 1 || stmts[0] == nil || stmts[0] == stmt {
+		t.Errorf("got %v", stmts)
+	}
+	if len(sqls) != 1 || sqls[0] != `DELETE FROM audit` {
+		t.Errorf("got %q", sqls)
+	}
+
+	err = db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if closes != 1 {
+		t.Errorf("got %d closes, want 1", closes)
+	}
+}
+
+func TestConn_BusyHandler(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "test.db")
+
+	db1, err := sqlite3.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db1.Close()
+
+	db2, err := sqlite3.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db2.Close()
+
+	err = db1.Exec(`BEGIN EXCLUSIVE`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calls int
+	err = db2.BusyHandler(func(count int) bool {
+		if count != calls {
+			t.Errorf("got %d, want %d", count, calls)
+		}
+		calls++
+		return count < 2
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db2.Exec(`BEGIN EXCLUSIVE`)
+	if !errors.Is(err, sqlite3.BUSY) {
+		t.Errorf("got %v, want sqlite3.BUSY", err)
+	}
+	if calls != 3 {
+		t.Errorf("got %d calls, want 3", calls)
+	}
+
+	err = db2.BusyHandler(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls = 0
+	err = db2.Exec(`BEGIN EXCLUSIVE`)
+	if !errors.Is(err, sqlite3.BUSY) {
+		t.Errorf("got %v, want sqlite3.BUSY", err)
+	}
+	if calls != 0 {
+		t.Errorf("got %d calls, want 0", calls)
+	}
+}
+
+func TestConn_BusyTimeout(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "test.db")
+
+	db1, err := sqlite3.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db1.Close()
+
+	db2, err := sqlite3.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db2.Close()
+
+	err = db1.Exec(`BEGIN EXCLUSIVE`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db2.BusyTimeout(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	db2.SetInterrupt(ctx)
+
+	start := time.Now()
+	err = db2.Exec(`BEGIN EXCLUSIVE`)
+	if !errors.Is(err, sqlite3.BUSY) {
+		t.Errorf("got %v, want sqlite3.BUSY", err)
+	}
+	if d := time.Since(start); d > 10*time.Second {
+		t.Errorf("busy wait took %v, want it to stop with the context", d)
+	}
+
+	db2.SetInterrupt(context.Background())
+
+	err = db2.BusyTimeout(50 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db2.Exec(`BEGIN EXCLUSIVE`)
+	if !errors.Is(err, sqlite3.BUSY) {
+		t.Errorf("got %v, want sqlite3.BUSY", err)
+	}
+}
+
+func TestConn_WALCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "test.db")
+
+	db, err := sqlite3.Open("file:" + file +
+		"?_pragma=locking_mode(exclusive)" +
+		"&_pragma=journal_mode(wal)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.WALAutoCheckpoint(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schemas []string
+	var pages int
+	db.WALHook(func(c *sqlite3.Conn, schema string, n int) error {
+		if c != db {
+			t.Errorf("got %v, want %v", c, db)
+		}
+		schemas = append(schemas, schema)
+		pages = n
+		return nil
+	})
+
+	err = db.Exec(`
+		CREATE TABLE test (col);
+		INSERT INTO test VALUES (1);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(schemas) != 2 || schemas[0] != "main" || schemas[1] != "main" {
+		t.Errorf("got %q", schemas)
+	}
+	if pages <= 0 {
+		t.Errorf("got %d pages", pages)
+	}
+
+	nLog, nCkpt, err := db.WALCheckpoint("main", sqlite3.CHECKPOINT_PASSIVE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nLog != pages || nCkpt != pages {
+		t.Errorf("got (%d, %d), want (%d, %d)", nLog, nCkpt, pages, pages)
+	}
+
+	_, _, err = db.WALCheckpoint("", sqlite3.CHECKPOINT_TRUNCATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fi, err := os.Stat(file + "-wal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Size() != 0 {
+		t.Errorf("got WAL size %d, want 0", fi.Size())
+	}
+
+	_, _, err = db.WALCheckpoint("missing", sqlite3.CHECKPOINT_PASSIVE)
+	if !errors.Is(err, sqlite3.ERROR) {
+		t.Errorf("got %v, want sqlite3.ERROR", err)
+	}
+
+	db.WALHook(nil)
+	schemas = nil
+
+	err = db.Exec(`INSERT INTO test VALUES (2)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schemas != nil {
+		t.Errorf("got %q, want nil", schemas)
+	}
+}
+
+func TestConn_PreUpdateHook(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`
+		CREATE TABLE test (id TEXT PRIMARY KEY, col) WITHOUT ROWID;
+		INSERT INTO test VALUES ('a', 1);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type change struct {
+		op       sqlite3.AuthorizerActionCode
+		table    string
+		old, new []string
+		count    int
+		depth    int
+	}
+
+	var got []change
+	db.PreUpdateHook(func(p sqlite3.PreUpdate) {
+		c := change{op: p.Op, table: p.Table, count: p.Count(), depth: p.Depth()}
+		if p.Schema != "main" {
+			t.Errorf("got %q, want main", p.Schema)
+		}
+		for i := 0; i < p.Count(); i++ {
+			if p.Op != sqlite3.AUTH_INSERT {
+				v, err := p.Old(i)
+				if err != nil {
+					t.Fatal(err)
+				}
+				c.old = append(c.old, v.Text())
+			}
+			if p.Op != sqlite3.AUTH_DELETE {
+				v, err := p.New(i)
+				if err != nil {
+					t.Fatal(err)
+				}
+				c.new = append(c.new, v.Text())
+			}
+		}
+		if p.Op == sqlite3.AUTH_INSERT {
+			if _, err := p.Old(0); !errors.Is(err, sqlite3.MISUSE) {
+				t.Errorf("got %v, want sqlite3.MISUSE", err)
+			}
+		}
+		got = append(got, c)
+	})
+
+	err = db.Exec(`
+		INSERT INTO test VALUES ('b', 2);
+		UPDATE test SET col = 3 WHERE id = 'a';
+		DELETE FROM test WHERE id = 'b';
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []change{
+		{sqlite3.AUTH_INSERT, "test", nil, []string{"b", "2"}, 2, 0},
+		{sqlite3.AUTH_UPDATE, "test", []string{"a", "1"}, []string{"a", "3"}, 2, 0},
+		{sqlite3.AUTH_DELETE, "test", []string{"b", "2"}, nil, 2, 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	db.PreUpdateHook(nil)
+
+	got = nil
+	err = db.Exec(`DELETE FROM test`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+}
+
+func TestConn_Serialize(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`
+		CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);
+		INSERT INTO users VALUES (1, 'alice'), (2, 'bob');
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := db.Serialize("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) == 0 || string(data[:16]) != "SQLite format 3\x00" {
+		t.Fatal("not a database file")
+	}
+
+	_, err = db.Serialize("missing")
+	if err == nil {
+		t.Error("want error")
+	}
+
+	dst, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+
+	err = dst.Deserialize("", data, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, _, err := dst.Prepare(`SELECT group_concat(name) FROM users`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+	if got := stmt.ColumnText(0); got != "alice,bob" {
+		t.Errorf("got %q, want alice,bob", got)
+	}
+	err = stmt.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = dst.Exec(`INSERT INTO users VALUES (3, 'carol')`)
+	if !errors.Is(err, sqlite3.READONLY) {
+		t.Errorf("got %v, want sqlite3.READONLY", err)
+	}
+
+	err = dst.Deserialize("main", data, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dst.Exec(`INSERT INTO users VALUES (3, 'carol')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConn_Limit(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	old := db.Limit(sqlite3.LIMIT_COLUMN, 2)
+	if old <= 2 {
+		t.Errorf("got %d, want more than 2", old)
+	}
+	if got := db.Limit(sqlite3.LIMIT_COLUMN, -1); got != 2 {
+		t.Errorf("got %d, want 2", got)
+	}
+
+	err = db.Exec(`CREATE TABLE test (a, b, c)`)
+	if err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestConn_Config(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	o, err := db.Config(sqlite3.DBCONFIG_DEFENSIVE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o {
+		t.Error("want false")
+	}
+
+	o, err = db.Config(sqlite3.DBCONFIG_DEFENSIVE, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !o {
+		t.Error("want true")
+	}
+
+	o, err = db.Config(sqlite3.DBCONFIG_ENABLE_VIEW, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o {
+		t.Error("want false")
+	}
+
+	err = db.Exec(`
+		CREATE TABLE test (col);
+		CREATE VIEW view AS SELECT * FROM test;
+		SELECT * FROM view;
+	`)
+	if err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestConn_ResetDatabase(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`
+		CREATE TABLE test (col);
+		INSERT INTO test VALUES (1);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.ResetDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, _, err := db.Prepare(`SELECT count(*) FROM sqlite_schema`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+	if got := stmt.ColumnInt(0); got != 0 {
+		t.Errorf("got %d, want 0", got)
+	}
+
+	o, err := db.Config(sqlite3.DBCONFIG_RESET_DATABASE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o {
+		t.Error("want false")
+	}
+}
+
+func TestConn_Status(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`
+		CREATE TABLE test (col);
+		INSERT INTO test VALUES (1);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cur, _, err := db.Status(sqlite3.DBSTATUS_SCHEMA_USED, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cur <= 0 {
+		t.Errorf("got %d, want positive", cur)
+	}
+
+	_, _, err = db.Status(sqlite3.DBSTATUS_CACHE_MISS, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cur, hi, err := db.GlobalStatus(sqlite3.STATUS_MEMORY_USED, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cur <= 0 || hi < cur {
+		t.Errorf("got %d, %d, want positive", cur, hi)
+	}
+
+	_, _, err = db.Status(sqlite3.DBStatus(100), false)
+	if !errors.Is(err, sqlite3.ERROR) {
+		t.Errorf("got %v, want sqlite3.ERROR", err)
+	}
+}
+
+func TestConn_Filename(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "test.db")
+	db, err := sqlite3.Open("file:" + filepath.ToSlash(file) + "?mode=rwc&cache=private")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if n := db.Filename("missing"); n != nil {
+		t.Error("want nil")
+	}
+
+	n := db.Filename("")
+	if got := n.Database(); got != file {
+		t.Errorf("got %q, want %q", got, file)
+	}
+	if got := n.Journal(); got != file+"-journal" {
+		t.Errorf("got %q, want %q", got, file+"-journal")
+	}
+	if got := n.WAL(); got != file+"-wal" {
+		t.Errorf("got %q, want %q", got, file+"-wal")
+	}
+	if got := n.URIParameter("cache"); got != "private" {
+		t.Errorf("got %q, want private", got)
+	}
+	if got := n.URIParameters(); !reflect.DeepEqual(got, url.Values{
+		"mode":  {"rwc"},
+		"cache": {"private"},
+	}) {
+		t.Errorf("got %v", got)
+	}
+
+	err = db.Exec(`ATTACH ':memory:' AS mem`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := db.Filename("mem").Database(); got != "" {
+		t.Errorf("got %q, want empty", got)
+	}
+}
+
+func TestConn_TxnState(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if ro, ok := db.ReadOnly(""); ro || !ok {
+		t.Errorf("got (%v, %v), want (false, true)", ro, ok)
+	}
+	if _, ok := db.ReadOnly("missing"); ok {
+		t.Error("want false")
+	}
+	if _, ok := db.TxnState("missing"); ok {
+		t.Error("want false")
+	}
+	if state, _ := db.TxnState(""); state != sqlite3.TXN_NONE {
+		t.Errorf("got %v, want TXN_NONE", state)
+	}
+
+	err = db.Exec(`
+		BEGIN;
+		CREATE TABLE test (col);
+		INSERT INTO test VALUES (1), (2);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state, _ := db.TxnState("main"); state != sqlite3.TXN_WRITE {
+		t.Errorf("got %v, want TXN_WRITE", state)
+	}
+	err = db.CacheFlush()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Exec(`COMMIT`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := db.TotalChanges(); got != 2 {
+		t.Errorf("got %d, want 2", got)
+	}
+	db.SetLastInsertRowID(42)
+	if got := db.LastInsertRowID(); got != 42 {
+		t.Errorf("got %d, want 42", got)
+	}
+	err = db.ReleaseMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConn_PrepareCached(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`CREATE TABLE test (a)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const sql = `SELECT * FROM test WHERE a = ?`
+	stmt1, _, err := db.PrepareCached(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = stmt1.BindInt(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = stmt1.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt2, _, err := db.PrepareCached(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stmt2.ExpandedSQL(); got != `SELECT * FROM test WHERE a = NULL` {
+		t.Errorf("got %q, want cleared bindings", got)
+	}
+
+	// Closing again doesn't affect the reused statement.
+	err = stmt2.BindInt(1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = stmt1.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stmt2.ExpandedSQL(); got != `SELECT * FROM test WHERE a = 2` {
+		t.Errorf("got %q, want bindings", got)
+	}
+	if got := stmt2.ColumnCount(); got != 1 {
+		t.Errorf("got %d, want 1", got)
+	}
+
+	// Schema changes are picked up.
+	err = db.Exec(`ALTER TABLE test ADD COLUMN b`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stmt2.Step() {
+		t.Error("want no rows")
+	}
+	if err := stmt2.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if got := stmt2.ColumnCount(); got != 2 {
+		t.Errorf("got %d, want 2", got)
+	}
+	err = stmt2.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Statements are reused, even after a failed step.
+	err = db.Exec(`CREATE UNIQUE INDEX test_a ON test (a)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const insert = `INSERT INTO test (a) VALUES (1)`
+	stmt3, _, err := db.PrepareCached(insert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stmt3.Status(sqlite3.STMTSTATUS_RUN, false); got != 0 {
+		t.Errorf("got %d runs, want a cache miss", got)
+	}
+	err = stmt3.Exec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = stmt3.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt4, _, err := db.PrepareCached(insert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stmt4.Status(sqlite3.STMTSTATUS_RUN, false); got != 1 {
+		t.Errorf("got %d runs, want a cache hit", got)
+	}
+	if stmt4.Step() {
+		t.Error("want no rows")
+	}
+	if err := stmt4.Err(); !errors.Is(err, sqlite3.CONSTRAINT) {
+		t.Errorf("got %v, want sqlite3.CONSTRAINT", err)
+	}
+	err = stmt4.Close()
+	if !errors.Is(err, sqlite3.CONSTRAINT) {
+		t.Errorf("got %v, want sqlite3.CONSTRAINT", err)
+	}
+
+	stmt5, _, err := db.PrepareCached(insert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stmt5.Status(sqlite3.STMTSTATUS_RUN, false); got != 2 {
+		t.Errorf("got %d runs, want a cache hit", got)
+	}
+	err = stmt5.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Disabling the cache.
+	db.SetStmtCacheSize(0)
+	stmt6, _, err := db.PrepareCached(insert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stmt6.Status(sqlite3.STMTSTATUS_RUN, false); got != 0 {
+		t.Errorf("got %d runs, want a cache miss", got)
+	}
+	if stmt6.Step() {
+		t.Error("want no rows")
+	}
+	err = stmt6.Close()
+	if !errors.Is(err, sqlite3.CONSTRAINT) {
+		t.Errorf("got %v, want sqlite3.CONSTRAINT", err)
+	}
+	stmt7, _, err := db.PrepareCached(insert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stmt7.Status(sqlite3.STMTSTATUS_RUN, false); got != 0 {
+		t.Errorf("got %d runs, want a cache miss", got)
+	}
+	err = stmt7.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Multiple statements are not cached.
+	db.SetStmtCacheSize(16)
+	for i := 0; i < 2; i++ {
+		stmt, tail, err := db.PrepareCached(`SELECT 1; SELECT 2`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tail != ` SELECT 2` {
+			t.Errorf("got %q", tail)
+		}
+		if got := stmt.Status(sqlite3.STMTSTATUS_RUN, false); got != 0 {
+			t.Errorf("got %d runs, want a cache miss", got)
+		}
+		err = stmt.Exec()
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = stmt.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ncruces/go-sqlite3"
+	_ "github.com/ncruces/go-sqlite3/embed"
+)
+
+func TestQueryAll(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, email TEXT, created DATETIME)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type Audit struct {
+		Created time.Time
+	}
+	type User struct {
+		*Audit
+		ID     int64   `db:"id"`
+		Name   string  `db:"name"`
+		Email  *string `db:"email"`
+		Ignore string  `db:"-"`
+	}
+
+	email := "alice@example.com"
+	now := time.Now().Round(0)
+	for _, u := range []User{
+		{ID: 1, Name: "alice", Email: &email, Audit: &Audit{now}},
+		{ID: 2, Name: "bob"},
+	} {
+		err = sqlite3.Exec(db, `INSERT INTO users VALUES (:id, @name, $email, :created)`, &u)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	users, err := sqlite3.QueryAll[User](db, `SELECT * FROM users ORDER BY id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("got %d users, want 2", len(users))
+	}
+	if u := users[0]; u.ID != 1 || u.Name != "alice" || u.Email == nil || *u.Email != email || !u.Created.Equal(now) {
+		t.Errorf("got %+v", u)
+	}
+	if u := users[1]; u.ID != 2 || u.Name != "bob" || u.Email != nil || !u.Created.IsZero() {
+		t.Errorf("got %+v", u)
+	}
+
+	names, err := sqlite3.QueryAll[string](db, `SELECT name FROM users WHERE id > ?`, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "alice" || names[1] != "bob" {
+		t.Errorf("got %v", names)
+	}
+
+	user, err := sqlite3.QueryOne[User](db, `SELECT id, name FROM users WHERE name = :name`,
+		map[string]any{"name": "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != 2 {
+		t.Errorf("got %+v", user)
+	}
+
+	_, err = sqlite3.QueryOne[User](db, `SELECT id FROM users WHERE id = 3`)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("got %v, want sql.ErrNoRows", err)
+	}
+
+	_, err = sqlite3.QueryAll[User](db, `SELECT 1 AS missing`)
+	if err == nil {
+		t.Error("want error")
+	}
+
+	err = sqlite3.Exec(db, `SELECT :missing`, user)
+	if err == nil {
+		t.Error("want error")
+	}
+
+	type audit struct {
+		Created time.Time
+	}
+	type hidden struct {
+		*audit
+		ID int64 `db:"id,key"`
+	}
+	_, err = sqlite3.QueryAll[hidden](db, `SELECT id, created FROM users`)
+	if err == nil {
+		t.Error("want error")
+	}
+	ids, err := sqlite3.QueryAll[hidden](db, `SELECT id FROM users ORDER BY id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0].ID != 1 || ids[0].audit != nil {
+		t.Errorf("got %+v", ids)
+	}
+}
@@ -0,0 +1,305 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ncruces/go-sqlite3"
+	_ "github.com/ncruces/go-sqlite3/embed"
+)
+
+func TestSession(t *testing.T) {
+	t.Parallel()
+
+	src, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+
+	dst, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+
+	const schema = `
+		CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);
+		CREATE TABLE other (id INTEGER PRIMARY KEY, name TEXT);
+	`
+	err = src.Exec(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dst.Exec(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session, err := src.CreateSession("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	err = session.Attach("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = src.Exec(`
+		INSERT INTO users VALUES (1, 'alice'), (2, 'bob');
+		INSERT INTO other VALUES (1, 'skipped');
+		UPDATE users SET name = 'carol' WHERE id = 2;
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changeset, err := session.Changeset()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changeset) == 0 {
+		t.Fatal("empty changeset")
+	}
+
+	patchset, err := session.Patchset()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(patchset) == 0 {
+		t.Fatal("empty patchset")
+	}
+
+	err = session.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Conflicting row in the destination.
+	err = dst.Exec(`INSERT INTO users VALUES (1, 'dave')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var conflicts []sqlite3.ConflictKind
+	err = dst.ApplyChangeset(changeset,
+		func(table string) bool {
+			return table == "users"
+		},
+		func(kind sqlite3.ConflictKind, iter sqlite3.ChangesetIterator) sqlite3.ConflictResolution {
+			conflicts = append(conflicts, kind)
+
+			table, ncol, op, _, err := iter.Operation()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if table != "users" || ncol != 2 || op != sqlite3.AUTH_INSERT {
+				t.Errorf("got (%q, %d, %v)", table, ncol, op)
+			}
+
+			v, err := iter.Conflict(1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := v.Text(); got != "dave" {
+				t.Errorf("got %q, want dave", got)
+			}
+			return sqlite3.CHANGESET_REPLACE
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(conflicts) != 1 || conflicts[0] != sqlite3.CHANGESET_CONFLICT {
+		t.Errorf("got %v, want [CHANGESET_CONFLICT]", conflicts)
+	}
+
+	stmt, _, err := dst.Prepare(`
+		SELECT group_concat(name) FROM (SELECT name FROM users ORDER BY id)
+		UNION ALL
+		SELECT count(*) FROM other
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+	if got := stmt.ColumnText(0); got != "alice,carol" {
+		t.Errorf("got %q, want alice,carol", got)
+	}
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+	if got := stmt.ColumnInt(0); got != 0 {
+		t.Errorf("got %d rows, want 0", got)
+	}
+	err = stmt.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Abort on conflict by default.
+	err = dst.ApplyChangeset(changeset, nil, nil)
+	if !errors.Is(err, sqlite3.ABORT) {
+		t.Errorf("got %v, want sqlite3.ABORT", err)
+	}
+}
+
+func TestChangeset(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record := func(sql string) []byte {
+		session, err := db.CreateSession("main")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer session.Close()
+
+		err = session.Attach("users")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = db.Exec(sql)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		err = session.ChangesetStream(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+
+	insert := record(`INSERT INTO users VALUES (1, 'alice'), (2, 'bob')`)
+	update := record(`UPDATE users SET name = 'carol' WHERE id = 2`)
+
+	changeset, err := db.ConcatChangesets(insert, update)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	iter, err := db.IterateChangesetStream(bytes.NewReader(changeset))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer iter.Close()
+	for iter.Next() {
+		_, _, op, _, err := iter.Operation()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if op != sqlite3.AUTH_INSERT {
+			t.Errorf("got %v, want AUTH_INSERT", op)
+		}
+		pk, err := iter.PrimaryKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pk) != 2 || !pk[0] || pk[1] {
+			t.Errorf("got %v, want [true false]", pk)
+		}
+		v, err := iter.New(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, v.Text())
+	}
+	if err := iter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] == names[1] || (names[0] != "carol" && names[1] != "carol") {
+		t.Errorf("got %v, want alice and carol", names)
+	}
+
+	// Undo everything.
+	var inverse bytes.Buffer
+	err = db.InvertChangesetStream(&inverse, bytes.NewReader(changeset))
+	if err != nil {
+		t.Fatal(err)
+	}
+	inverted, err := db.InvertChangeset(changeset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(inverted, inverse.Bytes()) {
+		t.Error("streaming and non-streaming inverses differ")
+	}
+
+	err = db.ApplyChangesetStream(&inverse, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, _, err := db.Prepare(`SELECT count(*) FROM users`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+	if got := stmt.ColumnInt(0); got != 0 {
+		t.Errorf("got %d rows, want 0", got)
+	}
+	stmt.Close()
+
+	// Apply a remote change, then rebase a local one on top of it.
+	err = db.Exec(`INSERT INTO users VALUES (1, 'local')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rebase, err := db.ApplyChangesetRebase(insert, nil,
+		func(sqlite3.ConflictKind, sqlite3.ChangesetIterator) sqlite3.ConflictResolution {
+			return sqlite3.CHANGESET_REPLACE
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rebaser, err := db.CreateRebaser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rebaser.Close()
+
+	err = rebaser.Configure(rebase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rebased, err := rebaser.Rebase(update)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	err = rebaser.RebaseStream(&buf, bytes.NewReader(update))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(rebased, buf.Bytes()) {
+		t.Error("streaming and non-streaming rebases differ")
+	}
+	err = rebaser.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
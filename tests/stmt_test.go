@@ -2,7 +2,9 @@ package tests
 
 import (
 	"encoding/json"
+	"errors"
 	"math"
+	"strings"
 	"testing"
 	"time"
 
@@ -652,3 +654,309 @@ func TestStmt_Error(t *testing.T) {
 		t.Log(err)
 	}
 }
+
+func TestStmt_ColumnOrigin(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL COLLATE NOCASE)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, _, err := db.Prepare(`SELECT name AS n, 1 + id FROM users`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	if got := stmt.ColumnDatabaseName(0); got != "main" {
+		t.Errorf("got %q, want main", got)
+	}
+	if got := stmt.ColumnTableName(0); got != "users" {
+		t.Errorf("got %q, want users", got)
+	}
+	if got := stmt.ColumnOriginName(0); got != "name" {
+		t.Errorf("got %q, want name", got)
+	}
+	if got := stmt.ColumnTableName(1); got != "" {
+		t.Errorf("got %q, want empty", got)
+	}
+
+	declType, collSeq, notNull, primaryKey, autoInc, err := db.TableColumnMetadata("", "users", "name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if declType != "TEXT" || collSeq != "NOCASE" || !notNull || primaryKey || autoInc {
+		t.Errorf("got (%q, %q, %v, %v, %v)", declType, collSeq, notNull, primaryKey, autoInc)
+	}
+
+	_, _, notNull, primaryKey, autoInc, err = db.TableColumnMetadata("main", "users", "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notNull || !primaryKey || !autoInc {
+		t.Errorf("got (%v, %v, %v)", notNull, primaryKey, autoInc)
+	}
+
+	_, _, _, _, _, err = db.TableColumnMetadata("", "missing", "")
+	if err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestStmt_SQL(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`CREATE TABLE test (col UNIQUE)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const sql = `INSERT INTO test VALUES (?)`
+	stmt, _, err := db.Prepare(sql + `; SELECT 1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	err = stmt.BindText(1, "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := stmt.SQL(); got != sql {
+		t.Errorf("got %q, want %q", got, sql)
+	}
+	if got := stmt.ExpandedSQL(); got != `INSERT INTO test VALUES ('abc')` {
+		t.Errorf("got %q", got)
+	}
+
+	lit1, _, err := db.Prepare(`SELECT * FROM test WHERE col = 'abc'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lit1.Close()
+
+	lit2, _, err := db.Prepare(`SELECT * FROM test WHERE col = 123`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lit2.Close()
+
+	if n1, n2 := lit1.NormalizedSQL(), lit2.NormalizedSQL(); n1 != n2 || n1 == "" {
+		t.Errorf("got %q and %q", n1, n2)
+	}
+
+	err = stmt.Exec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = stmt.Exec()
+	if !errors.Is(err, sqlite3.CONSTRAINT_UNIQUE) {
+		t.Fatalf("got %v, want sqlite3.CONSTRAINT_UNIQUE", err)
+	}
+
+	var serr *sqlite3.Error
+	if !errors.As(err, &serr) {
+		t.Fatalf("got %T, want sqlite3.Error", err)
+	}
+	if got := serr.ExpandedSQL(); got != `INSERT INTO test VALUES ('abc')` {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestStmt_QueryPlan(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Exec(`
+		CREATE TABLE test (a INTEGER PRIMARY KEY, b);
+		INSERT INTO test (b) SELECT value FROM generate_series(1, 100);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, _, err := db.Prepare(`SELECT b FROM test WHERE a = ?`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	err = stmt.BindInt(1, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := stmt.QueryPlan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != 1 || !strings.HasPrefix(plan[0].Detail, "SEARCH test USING INTEGER PRIMARY KEY") {
+		t.Fatalf("got %v", plan)
+	}
+	if got := sqlite3.FormatQueryPlan(plan); got != "QUERY PLAN\n`--"+plan[0].Detail+"\n" {
+		t.Errorf("got %q", got)
+	}
+
+	// The statement still works, with its bindings.
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+	if got := stmt.ColumnInt(0); got != 42 {
+		t.Errorf("got %d, want 42", got)
+	}
+	err = stmt.Reset()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status := stmt.ScanStatus()
+	if len(status) == 0 {
+		t.Fatal("want scan status")
+	}
+	if status[0].Loops != 1 || status[0].Visits != 1 {
+		t.Errorf("got %+v", status[0])
+	}
+
+	stmt.ResetScanStatus()
+	if got := stmt.ScanStatus(); got[0].Loops != 0 {
+		t.Errorf("got %+v", got[0])
+	}
+}
+
+func TestStmt_BindScan(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	type myInt int32
+	type myString string
+
+	now := time.Now().Round(0)
+	var nilPtr *int
+
+	stmt, _, err := db.Prepare(`SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	err = stmt.Bind(true, myInt(42), uint8(7), float32(0.5), myString("text"),
+		[]byte("blob"), now, nilPtr, sqlite3.JSON([]int{1, 2}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stmt.Step() {
+		t.Fatal(stmt.Err())
+	}
+
+	var (
+		b   bool
+		i   myInt
+		u   uint
+		f   float64
+		str string
+		buf []byte
+		tm  time.Time
+		ptr *int
+		arr []int
+	)
+	err = stmt.Scan(&b, &i, &u, &f, &str, &buf, &tm, &ptr, sqlite3.JSON(&arr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !b || i != 42 || u != 7 || f != 0.5 || str != "text" || string(buf) != "blob" {
+		t.Errorf("got %v %v %v %v %q %q", b, i, u, f, str, buf)
+	}
+	if !tm.Equal(now) {
+		t.Errorf("got %v, want %v", tm, now)
+	}
+	if ptr != nil {
+		t.Errorf("got %v, want nil", ptr)
+	}
+	if len(arr) != 2 || arr[0] != 1 || arr[1] != 2 {
+		t.Errorf("got %v", arr)
+	}
+
+	err = stmt.Scan(&b)
+	if err == nil {
+		t.Error("want error")
+	}
+	err = stmt.Reset()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt2, _, err := db.Prepare(`SELECT :a, @b, $a`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt2.Close()
+
+	err = stmt2.BindNamed(map[string]any{"a": 1, "@b": "two", "c": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stmt2.Step() {
+		t.Fatal(stmt2.Err())
+	}
+
+	var a1, a3 *int
+	var b2 any
+	err = stmt2.Scan(&a1, &b2, &a3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a1 == nil || *a1 != 1 || b2 != "two" || a3 == nil || *a3 != 1 {
+		t.Errorf("got %v %v %v", a1, b2, a3)
+	}
+
+	err = stmt2.Bind(struct{}{})
+	if err == nil {
+		t.Error("want error")
+	}
+
+	stmt3, _, err := db.Prepare(`SELECT x'01' UNION ALL SELECT x'02'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt3.Close()
+
+	var blob []byte
+	var blobs [][]byte
+	for stmt3.Step() {
+		err = stmt3.Scan(&blob)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blobs = append(blobs, blob)
+	}
+	if err := stmt3.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(blobs) != 2 || string(blobs[0]) != "\x01" || string(blobs[1]) != "\x02" {
+		t.Errorf("got %x", blobs)
+	}
+}
@@ -0,0 +1,468 @@
+package tests
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ncruces/go-sqlite3"
+	_ "github.com/ncruces/go-sqlite3/embed"
+)
+
+func TestIndexInfo_HandleIn(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var filters int
+	err = sqlite3.CreateModule(db, "squares", nil,
+		func(db *sqlite3.Conn, _, _, _ string, _ ...string) (*squaresTable, error) {
+			err := db.DeclareVtab(`CREATE TABLE x(key INTEGER PRIMARY KEY, value)`)
+			return &squaresTable{filters: &filters}, err
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, _, err := db.Prepare(`SELECT value FROM squares WHERE key IN (1, 3, 5) ORDER BY value`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	var got []int
+	for stmt.Step() {
+		got = append(got, stmt.ColumnInt(0))
+	}
+	if err := stmt.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 3 || got[0] != 1 || got[1] != 9 || got[2] != 25 {
+		t.Errorf("got %v", got)
+	}
+	if filters != 1 {
+		t.Errorf("got %d filters, want 1", filters)
+	}
+}
+
+type squaresTable struct {
+	filters *int
+}
+
+func (*squaresTable) BestIndex(idx *sqlite3.IndexInfo) error {
+	for i, cst := range idx.Constraint {
+		if cst.Column == 0 && cst.Op == sqlite3.INDEX_CONSTRAINT_EQ && cst.Usable {
+			idx.ConstraintUsage[i] = sqlite3.IndexConstraintUsage{
+				ArgvIndex: 1,
+				Omit:      true,
+			}
+			if idx.HandleIn(i, true) {
+				idx.IdxNum = 2
+			} else {
+				idx.IdxNum = 1
+			}
+			idx.EstimatedCost = 1
+			return nil
+		}
+	}
+	return sqlite3.CONSTRAINT
+}
+
+func (t *squaresTable) Open() (sqlite3.VTabCursor, error) {
+	return &squaresCursor{filters: t.filters}, nil
+}
+
+type squaresCursor struct {
+	filters *int
+	keys    []int64
+	pos     int
+}
+
+func (c *squaresCursor) Filter(idxNum int, idxStr string, arg ...sqlite3.Value) error {
+	*c.filters++
+	c.pos = 0
+	c.keys = c.keys[:0]
+
+	if idxNum == 1 {
+		c.keys = append(c.keys, arg[0].Int64())
+		return nil
+	}
+
+	val, err := arg[0].InFirst()
+	for ; err == nil; val, err = arg[0].InNext() {
+		c.keys = append(c.keys, val.Int64())
+	}
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
+
+func (c *squaresCursor) Column(ctx *sqlite3.Context, n int) error {
+	key := c.keys[c.pos]
+	switch n {
+	case 0:
+		ctx.ResultInt64(key)
+	case 1:
+		ctx.ResultInt64(key * key)
+	}
+	return nil
+}
+
+func (c *squaresCursor) Next() error {
+	c.pos++
+	return nil
+}
+
+func (c *squaresCursor) EOF() bool {
+	return c.pos >= len(c.keys)
+}
+
+func (c *squaresCursor) RowID() (int64, error) {
+	return c.keys[c.pos], nil
+}
+
+func TestVTab_update(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	tab := &notesTable{db: db, rows: map[int64][2]string{}}
+	ctor := func(db *sqlite3.Conn, _, _, _ string, _ ...string) (*notesTable, error) {
+		err := db.DeclareVtab(`CREATE TABLE x(title, body)`)
+		return tab, err
+	}
+	err = sqlite3.CreateModule(db, "notes", ctor, ctor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Exec(`
+		CREATE VIRTUAL TABLE notes USING notes;
+		INSERT INTO notes (rowid, title, body) VALUES (1, 'a', 'long body');
+		UPDATE notes SET title = 'b' WHERE rowid = 1;
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tab.rows[1]; got != [2]string{"b", "long body"} {
+		t.Errorf("got %q", got)
+	}
+	if !tab.nochange {
+		t.Error("want nochange")
+	}
+	if tab.conflict != sqlite3.VTAB_ABORT {
+		t.Errorf("got %v, want VTAB_ABORT", tab.conflict)
+	}
+
+	err = db.Exec(`INSERT OR REPLACE INTO notes (rowid, title, body) VALUES (1, 'c', 'body')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tab.conflict != sqlite3.VTAB_REPLACE {
+		t.Errorf("got %v, want VTAB_REPLACE", tab.conflict)
+	}
+
+	err = db.Exec(`SELECT * FROM notes WHERE title = 'C' COLLATE NOCASE`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tab.collation != "NOCASE" {
+		t.Errorf("got %q, want NOCASE", tab.collation)
+	}
+
+	err = db.Exec(`SELECT DISTINCT title FROM notes`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tab.distinct != 2 {
+		t.Errorf("got %d, want 2", tab.distinct)
+	}
+}
+
+type notesTable struct {
+	db        *sqlite3.Conn
+	rows      map[int64][2]string
+	collation string
+	distinct  int
+	conflict  sqlite3.VTabConflictMode
+	nochange  bool
+}
+
+func (t *notesTable) BestIndex(idx *sqlite3.IndexInfo) error {
+	for i, cst := range idx.Constraint {
+		if cst.Column == 0 && cst.Usable {
+			t.collation = idx.Collation(i)
+		}
+	}
+	t.distinct = idx.Distinct()
+	return nil
+}
+
+func (t *notesTable) Update(arg ...sqlite3.Value) (int64, error) {
+	t.conflict = t.db.VTabOnConflict()
+
+	if len(arg) == 1 {
+		delete(t.rows, arg[0].Int64())
+		return 0, nil
+	}
+
+	rowid := arg[1].Int64()
+	row := [2]string{arg[2].Text(), arg[3].Text()}
+	if arg[3].NoChange() {
+		t.nochange = true
+		row[1] = t.rows[arg[0].Int64()][1]
+	}
+	if arg[0].Type() != sqlite3.NULL {
+		delete(t.rows, arg[0].Int64())
+	}
+	t.rows[rowid] = row
+	return rowid, nil
+}
+
+func (t *notesTable) Open() (sqlite3.VTabCursor, error) {
+	c := &notesCursor{tab: t}
+	for id := range t.rows {
+		c.ids = append(c.ids, id)
+	}
+	return c, nil
+}
+
+type notesCursor struct {
+	tab *notesTable
+	ids []int64
+	pos int
+}
+
+func (c *notesCursor) Filter(idxNum int, idxStr string, arg ...sqlite3.Value) error {
+	c.pos = 0
+	return nil
+}
+
+func (c *notesCursor) Column(ctx *sqlite3.Context, n int) error {
+	if n == 1 && ctx.VTabNoChange() {
+		return nil
+	}
+	ctx.ResultText(c.tab.rows[c.ids[c.pos]][n])
+	return nil
+}
+
+func (c *notesCursor) Next() error {
+	c.pos++
+	return nil
+}
+
+func (c *notesCursor) EOF() bool {
+	return c.pos >= len(c.ids)
+}
+
+func (c *notesCursor) RowID() (int64, error) {
+	return c.ids[c.pos], nil
+}
+
+func TestCreateModule_eponymousOnly(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = sqlite3.CreateEponymousOnlyModule(db, "squares",
+		func(db *sqlite3.Conn, _, _, _ string, _ ...string) (*squaresTable, error) {
+			err := db.DeclareVtab(`CREATE TABLE x(key INTEGER PRIMARY KEY, value)`)
+			return &squaresTable{filters: new(int)}, err
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Exec(`SELECT value FROM squares WHERE key = 2`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Exec(`CREATE VIRTUAL TABLE sq USING squares`)
+	if err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestCreateModule_shadowTables(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	create := func(db *sqlite3.Conn, _, schema, table string, _ ...string) (shadowTable, error) {
+		err := db.Exec(`CREATE TABLE ` + sqlite3.QuoteIdentifier(schema) + `.` +
+			sqlite3.QuoteIdentifier(table+"_data") + `(value)`)
+		if err != nil {
+			return shadowTable{}, err
+		}
+		err = db.DeclareVtab(`CREATE TABLE x(value)`)
+		return shadowTable{}, err
+	}
+	connect := func(db *sqlite3.Conn, _, _, _ string, _ ...string) (shadowTable, error) {
+		err := db.DeclareVtab(`CREATE TABLE x(value)`)
+		return shadowTable{}, err
+	}
+	err = sqlite3.CreateModule(db, "shadow", create, connect)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Exec(`
+		CREATE VIRTUAL TABLE tab USING shadow;
+		CREATE TABLE tab_other (value);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.Config(sqlite3.DBCONFIG_DEFENSIVE, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Exec(`SELECT * FROM tab_data`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Exec(`INSERT INTO tab_data VALUES (1)`)
+	if err == nil {
+		t.Error("want error")
+	}
+	err = db.Exec(`INSERT INTO tab_other VALUES (1)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+type shadowTable struct{}
+
+func (shadowTable) ShadowName(suffix string) bool { return suffix == "data" }
+
+func (shadowTable) BestIndex(*sqlite3.IndexInfo) error { return nil }
+
+func (shadowTable) Open() (sqlite3.VTabCursor, error) {
+	return &notesCursor{tab: &notesTable{}}, nil
+}
+
+func TestCreateTableFunction(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = sqlite3.CreateTableFunction(db, "repeat",
+		[]string{"n INTEGER", "value"}, []string{"value_in", "times"},
+		func(arg []sqlite3.Value, yield func(row ...any) bool) error {
+			times := arg[1].Int()
+			if times < 0 {
+				return errors.New("negative times")
+			}
+			for i := 0; i < times; i++ {
+				if !yield(i, arg[0]) {
+					break
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, _, err := db.Prepare(`
+		SELECT n, value, value_in, times FROM repeat WHERE value_in = x'cafe' AND times = 3`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	var n int
+	for ; stmt.Step(); n++ {
+		if got := stmt.ColumnInt(0); got != n {
+			t.Errorf("got %d, want %d", got, n)
+		}
+		if got := stmt.ColumnBlob(1, nil); string(got) != "\xca\xfe" {
+			t.Errorf("got %x", got)
+		}
+		if got := stmt.ColumnType(2); got != sqlite3.BLOB {
+			t.Errorf("got %v", got)
+		}
+		if got := stmt.ColumnInt(3); got != 3 {
+			t.Errorf("got %d", got)
+		}
+	}
+	if err := stmt.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("got %d rows, want 3", n)
+	}
+
+	err = db.Exec(`SELECT * FROM repeat('x')`)
+	if err == nil {
+		t.Error("want error")
+	}
+	err = db.Exec(`SELECT * FROM repeat('x', -1)`)
+	if err == nil {
+		t.Error("want error")
+	}
+
+	var stopped int
+	err = sqlite3.CreateTableFunction(db, "count_from",
+		[]string{"n"}, []string{"start"},
+		func(arg []sqlite3.Value, yield func(row ...any) bool) error {
+			for i := arg[0].Int64(); ; i++ {
+				if !yield(i) {
+					stopped++
+					return nil
+				}
+			}
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ns, err := sqlite3.QueryAll[int64](db, `SELECT n FROM count_from(10) LIMIT 3`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ns) != 3 || ns[0] != 10 || ns[2] != 12 {
+		t.Errorf("got %v", ns)
+	}
+	if stopped != 1 {
+		t.Errorf("got %d stops, want 1", stopped)
+	}
+
+	ns, err = sqlite3.QueryAll[int64](db, `
+		WITH t(s) AS (VALUES (1), (5))
+		SELECT (SELECT n FROM count_from(s) LIMIT 1) FROM t`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ns) != 2 || ns[0] != 1 || ns[1] != 5 {
+		t.Errorf("got %v", ns)
+	}
+	if stopped != 3 {
+		t.Errorf("got %d stops, want 3", stopped)
+	}
+}
@@ -2,6 +2,7 @@ package sqlite3
 
 import (
 	"encoding/json"
+	"io"
 	"math"
 	"strconv"
 	"time"
@@ -159,6 +160,15 @@ func (v Value) rawBytes(ptr uint32) []byte {
 	return util.View(v.mod, ptr, r)
 }
 
+// NoChange returns true if and only if the value is unchanged
+// in a virtual table update operation.
+//
+// https://sqlite.org/c3ref/value_blob.html
+func (v Value) NoChange() bool {
+	r := v.call("sqlite3_value_nochange", v.protected())
+	return r != 0
+}
+
 // Pointer gets the pointer associated with this value,
 // or nil if it has no associated pointer.
 func (v Value) Pointer() any {
@@ -186,3 +196,38 @@ func (v Value) JSON(ptr any) error {
 	}
 	return json.Unmarshal(data, ptr)
 }
+
+// InFirst returns the first element
+// on the right-hand side of an IN constraint.
+// If the list is empty, InFirst returns [io.EOF].
+//
+// https://sqlite.org/c3ref/vtab_in_first.html
+func (v Value) InFirst() (Value, error) {
+	return v.in("sqlite3_vtab_in_first")
+}
+
+// InNext returns the next element
+// on the right-hand side of an IN constraint.
+// If there are no more elements, InNext returns [io.EOF].
+//
+// https://sqlite.org/c3ref/vtab_in_first.html
+func (v Value) InNext() (Value, error) {
+	return v.in("sqlite3_vtab_in_next")
+}
+
+func (v Value) in(name string) (Value, error) {
+	valPtr := v.new(ptrlen)
+	defer v.free(valPtr)
+
+	r := v.call(name, uint64(v.handle), uint64(valPtr))
+	if r == _DONE {
+		return Value{}, io.EOF
+	}
+	if err := v.error(r, 0); err != nil {
+		return Value{}, err
+	}
+	return Value{
+		sqlite: v.sqlite,
+		handle: util.ReadUint32(v.mod, valPtr),
+	}, nil
+}
@@ -13,6 +13,18 @@ import (
 //
 // https://sqlite.org/c3ref/create_module.html
 func CreateModule[T VTab](db *Conn, name string, create, connect VTabConstructor[T]) error {
+	return createModule(db, name, create, connect, false)
+}
+
+// CreateEponymousOnlyModule registers a new eponymous-only virtual table module name:
+// it can be used as a table-valued function, but not with CREATE VIRTUAL TABLE.
+//
+// https://sqlite.org/vtab.html#eponymous_only_virtual_tables
+func CreateEponymousOnlyModule[T VTab](db *Conn, name string, connect VTabConstructor[T]) error {
+	return createModule(db, name, nil, connect, true)
+}
+
+func createModule[T VTab](db *Conn, name string, create, connect VTabConstructor[T], eponymousOnly bool) error {
 	var flags int
 
 	const (
@@ -24,14 +36,14 @@ func CreateModule[T VTab](db *Conn, name string, create, connect VTabConstructor
 		VTAB_CHECKER     = 0x20
 		VTAB_TX          = 0x40
 		VTAB_SAVEPOINTER = 0x80
+		VTAB_SHADOWTABS  = 0x100
 	)
 
+	vtab := reflect.TypeOf(connect).Out(0)
 	if create != nil {
 		flags |= VTAB_CREATOR
 	}
-
-	vtab := reflect.TypeOf(connect).Out(0)
-	if implements[VTabDestroyer](vtab) {
+	if !eponymousOnly && implements[VTabDestroyer](vtab) {
 		flags |= VTAB_DESTROYER
 	}
 	if implements[VTabUpdater](vtab) {
@@ -52,6 +64,9 @@ func CreateModule[T VTab](db *Conn, name string, create, connect VTabConstructor
 	if implements[VTabSavepointer](vtab) {
 		flags |= VTAB_SAVEPOINTER
 	}
+	if implements[VTabShadowTabler](vtab) {
+		flags |= VTAB_SHADOWTABS
+	}
 
 	defer db.arena.mark()()
 	namePtr := db.arena.string(name)
@@ -102,11 +117,28 @@ func (c *Conn) VtabConfig(op VtabConfigOption, args ...any) error {
 	return c.error(r)
 }
 
+// VTabOnConflict determines the current conflict resolution mode
+// from within [VTabUpdater.Update].
+//
+// https://sqlite.org/c3ref/vtab_on_conflict.html
+func (c *Conn) VTabOnConflict() VTabConflictMode {
+	r := c.call("sqlite3_vtab_on_conflict", uint64(c.handle))
+	return VTabConflictMode(r)
+}
+
 // VTabConstructor is a virtual table constructor function.
 type VTabConstructor[T VTab] func(db *Conn, module, schema, table string, arg ...string) (T, error)
 
 type module[T VTab] [2]VTabConstructor[T]
 
+func (module[T]) shadowName(suffix string) bool {
+	var vtab T
+	if s, ok := any(vtab).(VTabShadowTabler); ok {
+		return s.ShadowName(suffix)
+	}
+	return false
+}
+
 // A VTab describes a particular instance of the virtual table.
 // A VTab may optionally implement [io.Closer] to free resources.
 //
@@ -125,6 +157,23 @@ type VTabDestroyer interface {
 	Destroy() error
 }
 
+// A VTabShadowTabler allows a virtual table to protect the content
+// of shadow tables from being corrupted by hostile SQL.
+//
+// For a virtual table named "mumble", ShadowName reports
+// if "mumble_suffix" is one of its shadow tables.
+// In defensive mode ([DBCONFIG_DEFENSIVE]), shadow tables are read-only
+// to ordinary SQL, and can only be written by the virtual table itself.
+//
+// SQLite calls ShadowName without a virtual table instance,
+// so it's called on the zero value of the type,
+// and must not depend on its receiver.
+type VTabShadowTabler interface {
+	VTab
+	// https://sqlite.org/vtab.html#the_xshadowname_method
+	ShadowName(suffix string) bool
+}
+
 // A VTabUpdater allows a virtual table to be updated.
 type VTabUpdater interface {
 	VTab
@@ -273,6 +322,42 @@ func (idx *IndexInfo) RHSValue(column int) (Value, error) {
 	}, nil
 }
 
+// HandleIn reports if a constraint is an IN operator
+// that can be processed all at once,
+// and signals whether the virtual table wants to process it that way.
+// If handle is true, the right-hand operand passed to [VTabCursor.Filter]
+// is the whole IN list, iterated with [Value.InFirst] and [Value.InNext].
+//
+// https://sqlite.org/c3ref/vtab_in.html
+func (idx *IndexInfo) HandleIn(constraint int, handle bool) bool {
+	var b uint64
+	if handle {
+		b = 1
+	}
+	r := idx.c.call("sqlite3_vtab_in", uint64(idx.handle), uint64(constraint), b)
+	return r != 0
+}
+
+// Collation returns the name of the collation for a constraint.
+//
+// https://sqlite.org/c3ref/vtab_collation.html
+func (idx *IndexInfo) Collation(constraint int) string {
+	r := idx.c.call("sqlite3_vtab_collation", uint64(idx.handle), uint64(constraint))
+	return util.ReadString(idx.c.mod, uint32(r), _MAX_NAME)
+}
+
+// Distinct determines if a query is DISTINCT.
+// It returns 0 if there's no DISTINCT, GROUP BY or ORDER BY clause,
+// 1 if rows only need to be ordered as requested,
+// 2 if rows need only be distinct (not ordered),
+// and 3 if rows need only be distinct, and ordered.
+//
+// https://sqlite.org/c3ref/vtab_distinct.html
+func (idx *IndexInfo) Distinct() int {
+	r := idx.c.call("sqlite3_vtab_distinct", uint64(idx.handle))
+	return int(r)
+}
+
 func (idx *IndexInfo) load() {
 	// https://sqlite.org/c3ref/index_info.html
 	mod := idx.c.mod
@@ -367,6 +452,19 @@ const (
 	INDEX_SCAN_UNIQUE IndexScanFlag = 1
 )
 
+// VTabConflictMode is a virtual table conflict resolution mode.
+//
+// https://sqlite.org/c3ref/c_fail.html
+type VTabConflictMode uint8
+
+const (
+	VTAB_ROLLBACK VTabConflictMode = 1
+	VTAB_IGNORE   VTabConflictMode = 2
+	VTAB_FAIL     VTabConflictMode = 3
+	VTAB_ABORT    VTabConflictMode = 4
+	VTAB_REPLACE  VTabConflictMode = 5
+)
+
 func vtabModuleCallback(i int) func(_ context.Context, _ api.Module, _, _, _, _, _ uint32) uint32 {
 	return func(ctx context.Context, mod api.Module, pMod, argc, argv, ppVTab, pzErr uint32) uint32 {
 		arg := make([]reflect.Value, 1+argc)
@@ -388,6 +486,14 @@ func vtabModuleCallback(i int) func(_ context.Context, _ api.Module, _, _, _, _,
 	}
 }
 
+func vtabShadowNameCallback(ctx context.Context, mod api.Module, pMod, zName uint32) uint32 {
+	module := vtabGetHandle(ctx, mod, pMod).(interface{ shadowName(string) bool })
+	if module.shadowName(util.ReadString(mod, zName, _MAX_NAME)) {
+		return 1
+	}
+	return 0
+}
+
 func vtabDisconnectCallback(ctx context.Context, mod api.Module, pVTab uint32) uint32 {
 	err := vtabDelHandle(ctx, mod, pVTab)
 	return vtabError(ctx, mod, 0, _PTR_ERROR, err)
@@ -3,6 +3,7 @@ package sqlite3_test
 import (
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/ncruces/go-sqlite3"
 	_ "github.com/ncruces/go-sqlite3/embed"
@@ -42,6 +43,45 @@ func ExampleCreateModule() {
 	// 8 8
 }
 
+func ExampleCreateTableFunction() {
+	db, err := sqlite3.Open(":memory:")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	err = sqlite3.CreateTableFunction(db, "split",
+		[]string{"part TEXT"}, []string{"str", "sep"},
+		func(arg []sqlite3.Value, yield func(row ...any) bool) error {
+			for _, part := range strings.Split(arg[0].Text(), arg[1].Text()) {
+				if !yield(part) {
+					break
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	stmt, _, err := db.Prepare(`SELECT part FROM split('a,b,c', ',')`)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer stmt.Close()
+
+	for stmt.Step() {
+		fmt.Println(stmt.ColumnText(0))
+	}
+	if err := stmt.Err(); err != nil {
+		log.Fatal(err)
+	}
+	// Output:
+	// a
+	// b
+	// c
+}
+
 type seriesTable struct{}
 
 func (seriesTable) BestIndex(idx *sqlite3.IndexInfo) error {